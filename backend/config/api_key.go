@@ -0,0 +1,24 @@
+package config
+
+import (
+	"log"
+	"time"
+)
+
+// defaultAPIKeyGracePeriod 轮换API密钥后旧密钥的默认有效期
+const defaultAPIKeyGracePeriod = 24 * time.Hour
+
+// APIKeyGracePeriod 获取旧API密钥的宽限期，可通过 API_KEY_GRACE_PERIOD 配置（如 "48h"）
+func APIKeyGracePeriod() time.Duration {
+	value := getEnv("API_KEY_GRACE_PERIOD", "")
+	if value == "" {
+		return defaultAPIKeyGracePeriod
+	}
+
+	period, err := time.ParseDuration(value)
+	if err != nil || period < 0 {
+		log.Printf("API_KEY_GRACE_PERIOD 配置无效: %s，使用默认值", value)
+		return defaultAPIKeyGracePeriod
+	}
+	return period
+}
@@ -9,6 +9,7 @@ import (
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 	"app_management/models"
+	"app_management/utils"
 )
 
 var DB *gorm.DB
@@ -45,11 +46,27 @@ func InitDatabase() {
 		&models.Version{},
 		&models.MemberLevel{},
 		&models.AuditLog{},
+		&models.APIKey{},
+		&models.VersionArtifact{},
+		&models.SigningKey{},
+		&models.ChangelogEntry{},
+		&models.LocalizedChangelog{},
+		&models.PermissionSchema{},
+		&models.AppMember{},
 	)
 	if err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
 
+	// 将历史明文API密钥迁移为哈希
+	migrateAPIKeyHashes()
+
+	// 加密历史明文保存的签名私钥
+	migrateSigningKeySecrets()
+
+	// 重新渲染历史版本的更新日志
+	renderChangelogs()
+
 	// 创建数据库索引
 	createIndexes()
 
@@ -63,6 +80,9 @@ func createIndexes() {
 	DB.Exec("CREATE INDEX IF NOT EXISTS idx_applications_status ON applications(status)")
 	DB.Exec("CREATE INDEX IF NOT EXISTS idx_applications_created_at ON applications(created_at)")
 
+	// API密钥表索引
+	DB.Exec("CREATE INDEX IF NOT EXISTS idx_api_keys_revoked_at ON api_keys(revoked_at)")
+
 	// 版本表索引
 	DB.Exec("CREATE INDEX IF NOT EXISTS idx_versions_app_id ON versions(app_id)")
 	DB.Exec("CREATE INDEX IF NOT EXISTS idx_versions_created_at ON versions(created_at)")
@@ -85,6 +105,166 @@ func createIndexes() {
 	log.Println("Database indexes created successfully")
 }
 
+// migrateAPIKeyHashes 将旧版本中明文保存的API密钥转换为哈希加前缀，并删除明文列
+// 回填在事务中进行，确认所有记录都已有哈希后才删除明文列，任何一步失败都会中止启动，避免丢失密钥
+func migrateAPIKeyHashes() {
+	migrator := DB.Migrator()
+
+	if migrator.HasColumn(&models.Application{}, "api_key") {
+		if !migrator.HasColumn(&models.Application{}, "api_key_hash") || !migrator.HasColumn(&models.Application{}, "api_key_prefix") {
+			log.Fatal("Failed to migrate application API keys: hash columns do not exist")
+		}
+		hasPrevious := migrator.HasColumn(&models.Application{}, "previous_api_key")
+
+		var rows []struct {
+			ID             uint
+			APIKey         string
+			PreviousAPIKey string
+		}
+		err := DB.Transaction(func(tx *gorm.DB) error {
+			selectColumns := "id, api_key"
+			if hasPrevious {
+				selectColumns += ", previous_api_key"
+			}
+			if err := tx.Unscoped().Table("applications").Select(selectColumns).Scan(&rows).Error; err != nil {
+				return err
+			}
+
+			for _, row := range rows {
+				updates := map[string]interface{}{
+					"api_key_prefix": utils.APIKeyPrefix(row.APIKey),
+					"api_key_hash":   utils.HashAPIKey(row.APIKey),
+				}
+				if row.PreviousAPIKey != "" {
+					updates["previous_api_key_prefix"] = utils.APIKeyPrefix(row.PreviousAPIKey)
+					updates["previous_api_key_hash"] = utils.HashAPIKey(row.PreviousAPIKey)
+				}
+				if err := tx.Unscoped().Table("applications").Where("id = ?", row.ID).Updates(updates).Error; err != nil {
+					return fmt.Errorf("application %d: %w", row.ID, err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			log.Fatal("Failed to migrate application API keys:", err)
+		}
+
+		// 确认所有应用都已写入哈希后才删除明文列
+		var missing int64
+		if err := DB.Unscoped().Table("applications").
+			Where("api_key_hash IS NULL OR api_key_hash = ''").Count(&missing).Error; err != nil {
+			log.Fatal("Failed to verify application API key hashes:", err)
+		}
+		if missing > 0 {
+			log.Fatalf("Refusing to drop plaintext api_key column: %d applications have no key hash", missing)
+		}
+
+		if err := migrator.DropColumn(&models.Application{}, "api_key"); err != nil {
+			log.Fatal("Failed to drop plaintext api_key column:", err)
+		}
+		if hasPrevious {
+			if err := migrator.DropColumn(&models.Application{}, "previous_api_key"); err != nil {
+				log.Fatal("Failed to drop plaintext previous_api_key column:", err)
+			}
+		}
+		log.Printf("Migrated %d application API keys to hashes", len(rows))
+	}
+
+	if migrator.HasColumn(&models.APIKey{}, "key") {
+		if !migrator.HasColumn(&models.APIKey{}, "key_hash") || !migrator.HasColumn(&models.APIKey{}, "prefix") {
+			log.Fatal("Failed to migrate named API keys: hash columns do not exist")
+		}
+
+		var rows []struct {
+			ID  uint
+			Key string
+		}
+		err := DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Table("api_keys").Select("id, `key`").Scan(&rows).Error; err != nil {
+				return err
+			}
+
+			for _, row := range rows {
+				if err := tx.Table("api_keys").Where("id = ?", row.ID).Updates(map[string]interface{}{
+					"prefix":   utils.APIKeyPrefix(row.Key),
+					"key_hash": utils.HashAPIKey(row.Key),
+				}).Error; err != nil {
+					return fmt.Errorf("api key %d: %w", row.ID, err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			log.Fatal("Failed to migrate named API keys:", err)
+		}
+
+		// 确认所有命名密钥都已写入哈希后才删除明文列
+		var missing int64
+		if err := DB.Table("api_keys").Where("key_hash IS NULL OR key_hash = ''").Count(&missing).Error; err != nil {
+			log.Fatal("Failed to verify named API key hashes:", err)
+		}
+		if missing > 0 {
+			log.Fatalf("Refusing to drop plaintext key column: %d named API keys have no key hash", missing)
+		}
+
+		if err := migrator.DropColumn(&models.APIKey{}, "key"); err != nil {
+			log.Fatal("Failed to drop plaintext key column:", err)
+		}
+		log.Printf("Migrated %d named API keys to hashes", len(rows))
+	}
+}
+
+// migrateSigningKeySecrets 将旧版本中明文保存的签名私钥种子加密，失败时中止启动
+func migrateSigningKeySecrets() {
+	var keys []models.SigningKey
+	if err := DB.Select("id, private_key").Find(&keys).Error; err != nil {
+		log.Fatal("Failed to load signing keys:", err)
+	}
+
+	migrated := 0
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		for _, key := range keys {
+			if utils.IsSealedSecret(key.PrivateKey) {
+				continue
+			}
+			sealed, err := utils.SealSecret(SigningKeySecret(), key.PrivateKey)
+			if err != nil {
+				return fmt.Errorf("signing key %d: %w", key.ID, err)
+			}
+			if err := tx.Model(&models.SigningKey{}).Where("id = ?", key.ID).
+				UpdateColumn("private_key", sealed).Error; err != nil {
+				return fmt.Errorf("signing key %d: %w", key.ID, err)
+			}
+			migrated++
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatal("Failed to encrypt signing keys:", err)
+	}
+
+	if migrated > 0 {
+		log.Printf("Encrypted %d signing keys", migrated)
+	}
+}
+
+// renderChangelogs 旧版本直接把Markdown原文保存为HTML，启动时将这些记录重新渲染
+func renderChangelogs() {
+	var versions []models.Version
+	DB.Unscoped().Select("id, changelog_md").
+		Where("changelog_md <> '' AND changelog_html = changelog_md").
+		Find(&versions)
+
+	for _, v := range versions {
+		DB.Unscoped().Model(&models.Version{}).Where("id = ?", v.ID).
+			UpdateColumn("changelog_html", utils.RenderMarkdown(v.ChangelogMD))
+	}
+
+	if len(versions) > 0 {
+		log.Printf("Re-rendered %d version changelogs", len(versions))
+	}
+}
+
 // createDefaultAdmin 创建默认管理员用户
 func createDefaultAdmin() {
 	var count int64
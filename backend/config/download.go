@@ -0,0 +1,32 @@
+package config
+
+import (
+	"log"
+	"time"
+)
+
+// defaultDownloadTokenTTL 更新源中下载令牌的默认有效期
+const defaultDownloadTokenTTL = time.Hour
+
+// DownloadTokenTTL 获取下载令牌的有效期，可通过 DOWNLOAD_TOKEN_TTL 配置（如 "30m"）
+func DownloadTokenTTL() time.Duration {
+	value := getEnv("DOWNLOAD_TOKEN_TTL", "")
+	if value == "" {
+		return defaultDownloadTokenTTL
+	}
+
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl <= 0 {
+		log.Printf("DOWNLOAD_TOKEN_TTL 配置无效: %s，使用默认值", value)
+		return defaultDownloadTokenTTL
+	}
+	return ttl
+}
+
+// DownloadTokenSecret 获取签发下载令牌的密钥，可通过 DOWNLOAD_TOKEN_SECRET 配置，未配置时使用 JWT_SECRET
+func DownloadTokenSecret() string {
+	if secret := getEnv("DOWNLOAD_TOKEN_SECRET", ""); secret != "" {
+		return secret
+	}
+	return getEnv("JWT_SECRET", "your-secret-key")
+}
@@ -0,0 +1,24 @@
+package config
+
+import (
+	"log"
+	"time"
+)
+
+// defaultReleaseSchedulerInterval 定时发布调度器的默认检查间隔
+const defaultReleaseSchedulerInterval = 30 * time.Second
+
+// ReleaseSchedulerInterval 获取定时发布检查间隔，可通过 RELEASE_SCHEDULER_INTERVAL 配置（如 "1m"）
+func ReleaseSchedulerInterval() time.Duration {
+	value := getEnv("RELEASE_SCHEDULER_INTERVAL", "")
+	if value == "" {
+		return defaultReleaseSchedulerInterval
+	}
+
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		log.Printf("RELEASE_SCHEDULER_INTERVAL 配置无效: %s，使用默认值", value)
+		return defaultReleaseSchedulerInterval
+	}
+	return interval
+}
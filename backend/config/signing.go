@@ -0,0 +1,32 @@
+package config
+
+import (
+	"log"
+	"time"
+)
+
+// defaultSigningKeyGracePeriod 轮换签名密钥后旧公钥继续发布的默认时长
+const defaultSigningKeyGracePeriod = 7 * 24 * time.Hour
+
+// SigningKeyGracePeriod 获取旧签名公钥的发布时长，可通过 SIGNING_KEY_GRACE_PERIOD 配置（如 "72h"）
+func SigningKeyGracePeriod() time.Duration {
+	value := getEnv("SIGNING_KEY_GRACE_PERIOD", "")
+	if value == "" {
+		return defaultSigningKeyGracePeriod
+	}
+
+	period, err := time.ParseDuration(value)
+	if err != nil || period < 0 {
+		log.Printf("SIGNING_KEY_GRACE_PERIOD 配置无效: %s，使用默认值", value)
+		return defaultSigningKeyGracePeriod
+	}
+	return period
+}
+
+// SigningKeySecret 获取加密签名私钥的密钥，可通过 SIGNING_KEY_SECRET 配置，未配置时使用 JWT_SECRET
+func SigningKeySecret() string {
+	if secret := getEnv("SIGNING_KEY_SECRET", ""); secret != "" {
+		return secret
+	}
+	return getEnv("JWT_SECRET", "your-secret-key")
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"log"
+
+	"app_management/storage"
+)
+
+// Storage 构建产物存储
+var Storage storage.Storage
+
+// InitStorage 初始化构建产物存储，STORAGE_DRIVER 可选 filesystem（默认）或 s3
+func InitStorage() {
+	var err error
+	switch driver := getEnv("STORAGE_DRIVER", "filesystem"); driver {
+	case "s3":
+		Storage, err = storage.NewS3Storage(storage.S3Config{
+			Endpoint:  getEnv("S3_ENDPOINT", ""),
+			Region:    getEnv("S3_REGION", "us-east-1"),
+			Bucket:    getEnv("S3_BUCKET", ""),
+			AccessKey: getEnv("S3_ACCESS_KEY", ""),
+			SecretKey: getEnv("S3_SECRET_KEY", ""),
+		})
+	case "filesystem":
+		Storage, err = storage.NewFileSystemStorage(getEnv("STORAGE_PATH", "./data/artifacts"))
+	default:
+		log.Fatalf("不支持的存储驱动: %s", driver)
+	}
+	if err != nil {
+		log.Fatal("Failed to initialize storage:", err)
+	}
+
+	log.Println("构建产物存储初始化成功")
+}
@@ -31,9 +31,16 @@ func SetupTestDB(t *testing.T) {
 func CleanupTestDB(t *testing.T) {
 	if DB != nil {
 		// 清理测试数据
+		DB.Exec("DELETE FROM api_keys")
+		DB.Exec("DELETE FROM version_artifacts")
+		DB.Exec("DELETE FROM signing_keys")
+		DB.Exec("DELETE FROM changelog_entries")
+		DB.Exec("DELETE FROM localized_changelogs")
 		DB.Exec("DELETE FROM versions")
 		DB.Exec("DELETE FROM applications")
+		DB.Exec("DELETE FROM app_members")
 		DB.Exec("DELETE FROM member_levels")
+		DB.Exec("DELETE FROM permission_schemas")
 		DB.Exec("DELETE FROM audit_logs")
 		DB.Exec("DELETE FROM users")
 	}
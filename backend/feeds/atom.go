@@ -0,0 +1,147 @@
+package feeds
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// 订阅源的响应类型
+const (
+	AtomContentType = "application/atom+xml; charset=utf-8"
+	RSSContentType  = "application/rss+xml; charset=utf-8"
+)
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"feed"`
+	NS       string      `xml:"xmlns,attr"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Author   atomAuthor  `xml:"author"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Published string      `xml:"published"`
+	Updated   string      `xml:"updated"`
+	Content   atomContent `xml:"content"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// Atom 渲染 Atom 1.0 更新日志订阅源，每个版本一个条目
+func Atom(feed *Feed) ([]byte, error) {
+	result := atomFeed{
+		NS:       "http://www.w3.org/2005/Atom",
+		ID:       feed.ID,
+		Title:    feed.Title,
+		Subtitle: feed.Description,
+		Updated:  feed.LastModified().UTC().Format(time.RFC3339),
+		Author:   atomAuthor{Name: feed.author()},
+	}
+	if feed.Link != "" {
+		result.Links = append(result.Links, atomLink{Rel: "alternate", Href: feed.Link})
+	}
+	if feed.SelfLink != "" {
+		result.Links = append(result.Links, atomLink{Rel: "self", Href: feed.SelfLink})
+	}
+
+	for _, release := range feed.Releases {
+		updated := release.UpdatedAt
+		if updated.Before(release.PublishedAt) {
+			updated = release.PublishedAt
+		}
+		result.Entries = append(result.Entries, atomEntry{
+			ID:        feed.ID + ":versions:" + release.Version,
+			Title:     feed.Title + " " + release.Version,
+			Published: release.PublishedAt.UTC().Format(time.RFC3339),
+			Updated:   updated.UTC().Format(time.RFC3339),
+			Content:   atomContent{Type: "html", Body: release.NotesHTML},
+		})
+	}
+
+	return marshalXML(result)
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	SelfLink      *atomLink `xml:"atom:link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Description string  `xml:"description"`
+}
+
+type rssGUID struct {
+	IsPermaLink string `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// RSS 渲染 RSS 2.0 更新日志订阅源，每个版本一个条目
+func RSS(feed *Feed) ([]byte, error) {
+	result := rssFeed{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:         feed.Title,
+			Link:          feed.Link,
+			Description:   feed.Description,
+			LastBuildDate: feed.LastModified().UTC().Format(time.RFC1123Z),
+		},
+	}
+	// 订阅源自身的地址通过 atom:link 提供
+	if feed.SelfLink != "" {
+		result.Channel.SelfLink = &atomLink{Rel: "self", Type: "application/rss+xml", Href: feed.SelfLink}
+	}
+
+	for _, release := range feed.Releases {
+		result.Channel.Items = append(result.Channel.Items, rssItem{
+			Title:       feed.Title + " " + release.Version,
+			GUID:        rssGUID{IsPermaLink: "false", Value: feed.ID + ":versions:" + release.Version},
+			PubDate:     release.PublishedAt.UTC().Format(time.RFC1123Z),
+			Description: release.NotesHTML,
+		})
+	}
+
+	return marshalXML(result)
+}
+
+// marshalXML 输出带 XML 声明的缩进文档
+func marshalXML(v interface{}) ([]byte, error) {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
@@ -0,0 +1,55 @@
+// Package feeds 将版本信息渲染为更新框架和订阅阅读器使用的格式
+package feeds
+
+import (
+	"app_management/models"
+	"time"
+)
+
+// Release 渲染更新源所需的版本信息
+type Release struct {
+	Version       string
+	Channel       string
+	Mandatory     bool
+	NotesMarkdown string
+	NotesHTML     string
+	PublishedAt   time.Time
+	UpdatedAt     time.Time
+	// Artifact 与目标平台匹配的构建产物，URL 需为完整地址
+	Artifact *models.VersionArtifact
+	// Artifacts 版本的全部构建产物，用于同时包含多个平台的更新源
+	Artifacts []models.VersionArtifact
+}
+
+// Feed 一个应用的更新源
+type Feed struct {
+	ID          string // 订阅源的唯一标识，如 urn:app-management:apps:1
+	Title       string
+	Description string
+	Author      string    // 订阅源作者，为空时使用标题
+	Link        string    // 应用或站点页面地址
+	SelfLink    string    // 订阅源自身的地址
+	Releases    []Release // 按版本从新到旧排列
+}
+
+// LastModified 返回更新源中最近的发布或修改时间
+func (f *Feed) LastModified() time.Time {
+	var latest time.Time
+	for _, release := range f.Releases {
+		if release.PublishedAt.After(latest) {
+			latest = release.PublishedAt
+		}
+		if release.UpdatedAt.After(latest) {
+			latest = release.UpdatedAt
+		}
+	}
+	return latest
+}
+
+// author 返回订阅源作者，未设置时使用标题
+func (f *Feed) author() string {
+	if f.Author != "" {
+		return f.Author
+	}
+	return f.Title
+}
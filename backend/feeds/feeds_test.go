@@ -0,0 +1,186 @@
+package feeds
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"app_management/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testFeed() *Feed {
+	publishedAt := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	return &Feed{
+		Title:       "示例应用",
+		Description: "示例应用更新",
+		Link:        "https://updates.example.com/api/v1/external/appcast.xml",
+		Releases: []Release{
+			{
+				Version:       "1.1.0-beta.1",
+				Channel:       models.ChannelBeta,
+				NotesMarkdown: "- 新功能",
+				NotesHTML:     "<ul>\n<li>新功能</li>\n</ul>",
+				PublishedAt:   publishedAt.Add(24 * time.Hour),
+				Artifact: &models.VersionArtifact{
+					Platform: "macos", Arch: "universal", FileName: "app-1.1.0-beta.1.dmg",
+					URL: "https://cdn.example.com/app-1.1.0-beta.1.dmg", Size: 2048,
+				},
+			},
+			{
+				Version:       "1.0.0",
+				Channel:       models.ChannelStable,
+				Mandatory:     true,
+				NotesMarkdown: "首个版本",
+				NotesHTML:     "<p>首个版本</p>",
+				PublishedAt:   publishedAt,
+				Artifact: &models.VersionArtifact{
+					Platform: "macos", Arch: "universal", FileName: "app-1.0.0.dmg",
+					URL: "https://cdn.example.com/app-1.0.0.dmg", Size: 1024,
+					EdSignature:          "c2lnbmF0dXJl",
+					MinimumSystemVersion: "10.15",
+				},
+			},
+			{Version: "0.9.0", Channel: models.ChannelStable, PublishedAt: publishedAt},
+		},
+	}
+}
+
+// TestSparkle 测试 Sparkle appcast
+func TestSparkle(t *testing.T) {
+	data, err := Sparkle(testFeed())
+	assert.NoError(t, err)
+	xml := string(data)
+
+	assert.True(t, strings.HasPrefix(xml, "<?xml"))
+	assert.Contains(t, xml, `xmlns:sparkle="http://www.andymatuschak.org/xml-namespaces/sparkle"`)
+	assert.Contains(t, xml, "<sparkle:version>1.0.0</sparkle:version>")
+	assert.Contains(t, xml, "<sparkle:channel>beta</sparkle:channel>")
+	assert.Contains(t, xml, "<sparkle:minimumSystemVersion>10.15</sparkle:minimumSystemVersion>")
+	assert.Contains(t, xml, "<sparkle:criticalUpdate></sparkle:criticalUpdate>")
+	assert.Contains(t, xml, "<![CDATA[<p>首个版本</p>]]>")
+	assert.Contains(t, xml, `<enclosure url="https://cdn.example.com/app-1.0.0.dmg" length="1024" type="application/octet-stream" sparkle:edSignature="c2lnbmF0dXJl">`)
+	assert.Contains(t, xml, "Fri, 01 Mar 2024 08:00:00 +0000")
+
+	// 没有构建产物的版本不出现在 appcast 中
+	assert.NotContains(t, xml, "0.9.0")
+	assert.Equal(t, 1, strings.Count(xml, "<sparkle:channel>"))
+}
+
+// TestSquirrelMac 测试 Squirrel.Mac RELEASES JSON
+func TestSquirrelMac(t *testing.T) {
+	data, err := SquirrelMac(testFeed())
+	assert.NoError(t, err)
+
+	var result struct {
+		CurrentRelease string `json:"currentRelease"`
+		Releases       []struct {
+			Version  string `json:"version"`
+			UpdateTo struct {
+				PubDate string `json:"pub_date"`
+				URL     string `json:"url"`
+				Notes   string `json:"notes"`
+			} `json:"updateTo"`
+		} `json:"releases"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &result))
+	assert.Equal(t, "1.1.0-beta.1", result.CurrentRelease)
+	assert.Len(t, result.Releases, 2)
+	assert.Equal(t, "https://cdn.example.com/app-1.0.0.dmg", result.Releases[1].UpdateTo.URL)
+	assert.Equal(t, "2024-03-01T08:00:00Z", result.Releases[1].UpdateTo.PubDate)
+	assert.Equal(t, "首个版本", result.Releases[1].UpdateTo.Notes)
+}
+
+// TestSquirrelWindows 测试 Squirrel.Windows RELEASES 文件
+func TestSquirrelWindows(t *testing.T) {
+	feed := testFeed()
+	feed.Releases[1].Artifact.SHA1 = "e3f67244e4166a65310c816221a12685c83f8e6f"
+
+	// 缺少 SHA-1 的产物被跳过，文件名不符合格式时以标题生成包ID
+	assert.Equal(t, "E3F67244E4166A65310C816221A12685C83F8E6F app-1.0.0-full.nupkg 1024\n",
+		string(SquirrelWindows(feed)))
+
+	// 托管的安装包使用原始文件名，不输出下载地址
+	feed.Title = "Example App"
+	feed.Releases[0].Artifact = &models.VersionArtifact{
+		ID: 7, Platform: "windows", Arch: "x64", FileName: "ExampleApp-1.1.0-beta.1-full.nupkg",
+		URL: "/api/v1/external/download/7", Size: 4096, SHA1: "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+		StorageKey: "apps/1/versions/2/token/ExampleApp-1.1.0-beta.1-full.nupkg",
+	}
+	assert.Equal(t, "E3F67244E4166A65310C816221A12685C83F8E6F ExampleApp-1.0.0-full.nupkg 1024\n"+
+		"DA39A3EE5E6B4B0D3255BFEF95601890AFD80709 ExampleApp-1.1.0-beta.1-full.nupkg 4096\n",
+		string(SquirrelWindows(feed)))
+	assert.NotContains(t, string(SquirrelWindows(feed)), "/download/")
+
+	// 外部地址的安装包使用地址中的文件名
+	feed.Releases[1].Artifact.URL = "https://cdn.example.com/releases/ExampleApp-1.0.0-full.nupkg?sig=abc"
+	assert.Equal(t, "ExampleApp-1.0.0-full.nupkg", SquirrelWindowsFileName(feed, &feed.Releases[1]))
+}
+
+// TestTauri 测试 Tauri 更新器 JSON
+func TestTauri(t *testing.T) {
+	feed := testFeed()
+	_, err := Tauri(feed)
+	assert.ErrorIs(t, err, ErrNoRelease)
+
+	feed.Releases[1].Artifacts = []models.VersionArtifact{
+		{Platform: "macos", Arch: "universal", URL: "https://cdn.example.com/app.app.tar.gz", UpdaterSignature: "sig-universal"},
+		{Platform: "macos", Arch: "arm64", URL: "https://cdn.example.com/app-arm64.app.tar.gz", UpdaterSignature: "sig-arm64"},
+		{Platform: "windows", Arch: "x64", URL: "https://cdn.example.com/app-x64.msi.zip", UpdaterSignature: "sig-windows"},
+		{Platform: "linux", Arch: "x64", URL: "https://cdn.example.com/app.AppImage.tar.gz"},
+		{Platform: "android", URL: "https://cdn.example.com/app.apk", UpdaterSignature: "sig-android"},
+	}
+	data, err := Tauri(feed)
+	assert.NoError(t, err)
+
+	var result struct {
+		Version   string `json:"version"`
+		Notes     string `json:"notes"`
+		PubDate   string `json:"pub_date"`
+		Platforms map[string]struct {
+			Signature string `json:"signature"`
+			URL       string `json:"url"`
+		} `json:"platforms"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &result))
+	assert.Equal(t, "1.0.0", result.Version)
+	assert.Equal(t, "2024-03-01T08:00:00Z", result.PubDate)
+	assert.Len(t, result.Platforms, 3)
+	assert.Equal(t, "sig-universal", result.Platforms["darwin-x86_64"].Signature)
+	assert.Equal(t, "sig-arm64", result.Platforms["darwin-aarch64"].Signature)
+	assert.Equal(t, "https://cdn.example.com/app-x64.msi.zip", result.Platforms["windows-x86_64"].URL)
+}
+
+// TestAtomAndRSS 测试更新日志订阅源
+func TestAtomAndRSS(t *testing.T) {
+	feed := testFeed()
+	feed.ID = "urn:app-management:apps:1"
+	feed.Link = "https://example.com/"
+	feed.SelfLink = "https://example.com/api/v1/public/apps/1/feed.atom"
+	feed.Releases[1].UpdatedAt = time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), feed.LastModified())
+
+	data, err := Atom(feed)
+	assert.NoError(t, err)
+	atom := string(data)
+	assert.Contains(t, atom, `<feed xmlns="http://www.w3.org/2005/Atom">`)
+	assert.Contains(t, atom, "<updated>2024-03-05T00:00:00Z</updated>")
+	assert.Contains(t, atom, "<author>\n    <name>"+feed.Title+"</name>\n  </author>")
+	assert.Contains(t, atom, `<link rel="alternate" href="https://example.com/"></link>`)
+	assert.Contains(t, atom, `<link rel="self" href="https://example.com/api/v1/public/apps/1/feed.atom"></link>`)
+	assert.Contains(t, atom, "<id>urn:app-management:apps:1:versions:1.0.0</id>")
+	assert.Contains(t, atom, `<content type="html">&lt;p&gt;首个版本&lt;/p&gt;</content>`)
+	assert.Equal(t, 3, strings.Count(atom, "<entry>"))
+
+	data, err = RSS(feed)
+	assert.NoError(t, err)
+	rss := string(data)
+	assert.Contains(t, rss, `<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">`)
+	assert.Contains(t, rss, "<link>https://example.com/</link>")
+	assert.Contains(t, rss, `<atom:link rel="self" type="application/rss+xml" href="https://example.com/api/v1/public/apps/1/feed.atom"></atom:link>`)
+	assert.Contains(t, rss, "<lastBuildDate>Tue, 05 Mar 2024 00:00:00 +0000</lastBuildDate>")
+	assert.Contains(t, rss, `<guid isPermaLink="false">urn:app-management:apps:1:versions:1.0.0</guid>`)
+	assert.Equal(t, 3, strings.Count(rss, "<item>"))
+}
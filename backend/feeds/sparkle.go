@@ -0,0 +1,94 @@
+package feeds
+
+import (
+	"app_management/models"
+	"encoding/xml"
+	"strconv"
+	"time"
+)
+
+// SparkleContentType Sparkle appcast 的响应类型
+const SparkleContentType = "application/rss+xml; charset=utf-8"
+
+type sparkleRSS struct {
+	XMLName      xml.Name       `xml:"rss"`
+	Version      string         `xml:"version,attr"`
+	SparkleNS    string         `xml:"xmlns:sparkle,attr"`
+	DublinCoreNS string         `xml:"xmlns:dc,attr"`
+	Channel      sparkleChannel `xml:"channel"`
+}
+
+type sparkleChannel struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link,omitempty"`
+	Description string        `xml:"description,omitempty"`
+	Items       []sparkleItem `xml:"item"`
+}
+
+type sparkleItem struct {
+	Title                string           `xml:"title"`
+	PubDate              string           `xml:"pubDate"`
+	Version              string           `xml:"sparkle:version"`
+	ShortVersionString   string           `xml:"sparkle:shortVersionString"`
+	Channel              string           `xml:"sparkle:channel,omitempty"`
+	MinimumSystemVersion string           `xml:"sparkle:minimumSystemVersion,omitempty"`
+	CriticalUpdate       *struct{}        `xml:"sparkle:criticalUpdate"`
+	Description          sparkleCDATA     `xml:"description"`
+	Enclosure            sparkleEnclosure `xml:"enclosure"`
+}
+
+type sparkleCDATA struct {
+	Text string `xml:",cdata"`
+}
+
+type sparkleEnclosure struct {
+	URL         string `xml:"url,attr"`
+	Length      string `xml:"length,attr"`
+	Type        string `xml:"type,attr"`
+	EdSignature string `xml:"sparkle:edSignature,attr,omitempty"`
+}
+
+// Sparkle 渲染 Sparkle appcast，没有构建产物的版本会被跳过
+// 稳定版以外的版本带有 sparkle:channel，由 Sparkle 2 按客户端允许的渠道过滤
+func Sparkle(feed *Feed) ([]byte, error) {
+	rss := sparkleRSS{
+		Version:      "2.0",
+		SparkleNS:    "http://www.andymatuschak.org/xml-namespaces/sparkle",
+		DublinCoreNS: "http://purl.org/dc/elements/1.1/",
+		Channel: sparkleChannel{
+			Title:       feed.Title,
+			Link:        feed.Link,
+			Description: feed.Description,
+		},
+	}
+
+	for _, release := range feed.Releases {
+		if release.Artifact == nil {
+			continue
+		}
+
+		item := sparkleItem{
+			Title:                "Version " + release.Version,
+			PubDate:              release.PublishedAt.UTC().Format(time.RFC1123Z),
+			Version:              release.Version,
+			ShortVersionString:   release.Version,
+			MinimumSystemVersion: release.Artifact.MinimumSystemVersion,
+			Description:          sparkleCDATA{Text: release.NotesHTML},
+			Enclosure: sparkleEnclosure{
+				URL:         release.Artifact.URL,
+				Length:      strconv.FormatInt(release.Artifact.Size, 10),
+				Type:        "application/octet-stream",
+				EdSignature: release.Artifact.EdSignature,
+			},
+		}
+		if release.Channel != models.ChannelStable {
+			item.Channel = release.Channel
+		}
+		if release.Mandatory {
+			item.CriticalUpdate = &struct{}{}
+		}
+		rss.Channel.Items = append(rss.Channel.Items, item)
+	}
+
+	return marshalXML(rss)
+}
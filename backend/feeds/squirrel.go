@@ -0,0 +1,110 @@
+package feeds
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Squirrel 更新源的响应类型
+const (
+	SquirrelMacContentType     = "application/json; charset=utf-8"
+	SquirrelWindowsContentType = "text/plain; charset=utf-8"
+)
+
+type squirrelMacFeed struct {
+	CurrentRelease string               `json:"currentRelease"`
+	Releases       []squirrelMacRelease `json:"releases"`
+}
+
+type squirrelMacRelease struct {
+	Version  string            `json:"version"`
+	UpdateTo squirrelMacUpdate `json:"updateTo"`
+}
+
+type squirrelMacUpdate struct {
+	Version string `json:"version"`
+	PubDate string `json:"pub_date"`
+	Notes   string `json:"notes"`
+	Name    string `json:"name"`
+	URL     string `json:"url"`
+}
+
+// SquirrelMac 渲染 Squirrel.Mac 的 RELEASES JSON（Electron autoUpdater 的 json 服务类型）
+// currentRelease 为最新的有构建产物的版本，没有构建产物的版本会被跳过
+func SquirrelMac(feed *Feed) ([]byte, error) {
+	result := squirrelMacFeed{Releases: []squirrelMacRelease{}}
+	for _, release := range feed.Releases {
+		if release.Artifact == nil {
+			continue
+		}
+		if result.CurrentRelease == "" {
+			result.CurrentRelease = release.Version
+		}
+		result.Releases = append(result.Releases, squirrelMacRelease{
+			Version: release.Version,
+			UpdateTo: squirrelMacUpdate{
+				Version: release.Version,
+				PubDate: release.PublishedAt.UTC().Format(time.RFC3339),
+				Notes:   release.NotesMarkdown,
+				Name:    release.Version,
+				URL:     release.Artifact.URL,
+			},
+		})
+	}
+	return json.Marshal(result)
+}
+
+// SquirrelWindows 渲染 Squirrel.Windows 的 RELEASES 文件，每行为 "SHA1 文件名 大小"
+// Squirrel 按文件名相对 RELEASES 地址下载安装包；缺少 SHA-1 的产物无法被校验，会被跳过；按版本从旧到新输出
+func SquirrelWindows(feed *Feed) []byte {
+	var b strings.Builder
+	for i := len(feed.Releases) - 1; i >= 0; i-- {
+		artifact := feed.Releases[i].Artifact
+		if artifact == nil || artifact.SHA1 == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "%s %s %d\n", strings.ToUpper(artifact.SHA1), SquirrelWindowsFileName(feed, &feed.Releases[i]), artifact.Size)
+	}
+	return []byte(b.String())
+}
+
+// SquirrelWindowsFileName 返回版本在 RELEASES 中的安装包文件名 "<id>-<version>-full.nupkg"
+// 构建产物自身的文件名符合该格式时直接使用，否则以更新源标题生成包ID
+func SquirrelWindowsFileName(feed *Feed, release *Release) string {
+	suffix := "-" + release.Version + "-full.nupkg"
+	if artifact := release.Artifact; artifact != nil {
+		candidates := []string{artifact.FileName}
+		if u, err := url.Parse(artifact.URL); err == nil {
+			candidates = append(candidates, path.Base(u.Path))
+		}
+		for _, name := range candidates {
+			if len(name) > len(suffix) && strings.HasSuffix(name, suffix) && nupkgFileNameRegex.MatchString(name) {
+				return name
+			}
+		}
+	}
+	return squirrelPackageID(feed.Title) + suffix
+}
+
+// nupkgFileNameRegex 可直接用作下载路径的安装包文件名
+var nupkgFileNameRegex = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// squirrelPackageID 由标题生成包ID，只保留字母、数字、点和下划线，避免与版本号的分隔符混淆
+func squirrelPackageID(title string) string {
+	id := strings.Map(func(r rune) rune {
+		if r < 128 && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '_') {
+			return r
+		}
+		return -1
+	}, title)
+	if id == "" {
+		return "app"
+	}
+	return id
+}
@@ -0,0 +1,83 @@
+package feeds
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// TauriContentType Tauri 更新源的响应类型
+const TauriContentType = "application/json; charset=utf-8"
+
+// ErrNoRelease 没有可供更新框架使用的版本
+var ErrNoRelease = errors.New("没有可用的版本")
+
+// Tauri 目标系统名称
+var tauriOS = map[string]string{
+	"macos":   "darwin",
+	"windows": "windows",
+	"linux":   "linux",
+}
+
+// Tauri 目标架构名称，通用包同时适用于多个架构，未指定架构时视为 x86_64
+var tauriArchs = map[string][]string{
+	"x64":       {"x86_64"},
+	"x86":       {"i686"},
+	"arm64":     {"aarch64"},
+	"universal": {"x86_64", "aarch64"},
+	"":          {"x86_64"},
+}
+
+type tauriFeed struct {
+	Version   string                   `json:"version"`
+	Notes     string                   `json:"notes"`
+	PubDate   string                   `json:"pub_date"`
+	Platforms map[string]tauriPlatform `json:"platforms"`
+}
+
+type tauriPlatform struct {
+	Signature string `json:"signature"`
+	URL       string `json:"url"`
+}
+
+// Tauri 渲染 Tauri 更新器的静态 JSON，使用最新的带有已签名桌面端产物的版本
+// Tauri 要求每个平台都有签名，缺少 UpdaterSignature 的产物会被跳过；没有可用版本时返回 ErrNoRelease
+func Tauri(feed *Feed) ([]byte, error) {
+	for _, release := range feed.Releases {
+		platforms := tauriPlatforms(&release)
+		if len(platforms) == 0 {
+			continue
+		}
+
+		return json.Marshal(tauriFeed{
+			Version:   release.Version,
+			Notes:     release.NotesMarkdown,
+			PubDate:   release.PublishedAt.UTC().Format(time.RFC3339),
+			Platforms: platforms,
+		})
+	}
+	return nil, ErrNoRelease
+}
+
+// tauriPlatforms 将版本的构建产物转换为 Tauri 平台列表，精确架构优先于通用包
+func tauriPlatforms(release *Release) map[string]tauriPlatform {
+	platforms := make(map[string]tauriPlatform)
+	for _, exact := range []bool{false, true} {
+		for _, artifact := range release.Artifacts {
+			osName, ok := tauriOS[artifact.Platform]
+			if !ok || artifact.UpdaterSignature == "" {
+				continue
+			}
+			if exact != (artifact.Arch != "universal" && artifact.Arch != "") {
+				continue
+			}
+			for _, arch := range tauriArchs[artifact.Arch] {
+				platforms[osName+"-"+arch] = tauriPlatform{
+					Signature: artifact.UpdaterSignature,
+					URL:       artifact.URL,
+				}
+			}
+		}
+	}
+	return platforms
+}
@@ -1,8 +1,15 @@
 package main
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log"
+	"mime"
 	"net/http"
 	"strconv"
 	"time"
@@ -11,9 +18,11 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"app_management/config"
+	"app_management/feeds"
 	"app_management/middleware"
 	"app_management/models"
 	"app_management/services"
+	"app_management/utils"
 )
 
 func main() {
@@ -23,10 +32,26 @@ func main() {
 	// 初始化Redis
 	config.InitRedis()
 
+	// 初始化构建产物存储
+	config.InitStorage()
+
 	// 创建服务实例
 	appService := services.NewAppService()
 	authService := services.NewAuthService()
 	memberService := services.NewMemberService()
+	apiKeyService := services.NewAPIKeyService()
+	updateService := services.NewUpdateService()
+	artifactService := services.NewArtifactService()
+	signingService := services.NewSigningService()
+	feedService := services.NewFeedService()
+	changelogService := services.NewChangelogService()
+	permissionSchemaService := services.NewPermissionSchemaService()
+	appMemberService := services.NewAppMemberService()
+
+	// 启动定时发布调度器
+	releaseScheduler := services.NewReleaseScheduler(appService, config.ReleaseSchedulerInterval())
+	releaseScheduler.Start()
+	defer releaseScheduler.Stop()
 	cacheService := services.NewCacheService()
 
 	r := gin.Default()
@@ -34,7 +59,7 @@ func main() {
 	// 配置CORS
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: true,
@@ -325,7 +350,7 @@ func main() {
 					})
 				})
 
-				apps.DELETE("/:id", func(c *gin.Context) {
+				updateApp := func(c *gin.Context) {
 					id := c.Param("id")
 					appID, err := strconv.Atoi(id)
 					if err != nil {
@@ -336,81 +361,72 @@ func main() {
 						return
 					}
 
-					// 删除应用
-					err = appService.DeleteApplication(uint(appID))
-					if err != nil {
-						c.JSON(http.StatusInternalServerError, gin.H{
-							"code":    500,
-							"message": "删除应用失败",
+					var req models.UpdateApplicationRequest
+					if err := c.ShouldBindJSON(&req); err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "请求参数错误",
 							"error":   err.Error(),
 						})
 						return
 					}
 
-					// 清除相关缓存
-					cacheService.ClearApplicationCache()
-					cacheService.ClearVersionCache()
-					// 强制删除应用列表缓存
-					config.DeleteCache("apps:list")
-
-					c.JSON(http.StatusOK, gin.H{
-						"code":    200,
-						"message": "应用删除成功",
-						"data":    gin.H{"deletedId": appID},
-					})
-				})
-
-				apps.POST("/:id/versions", func(c *gin.Context) {
-					id := c.Param("id")
-					appID, err := strconv.Atoi(id)
+					// 更新应用
+					app, err := appService.UpdateApplication(uint(appID), &req)
+					if errors.Is(err, services.ErrAppNotFound) {
+						c.JSON(http.StatusNotFound, gin.H{
+							"code":    404,
+							"message": "应用不存在",
+						})
+						return
+					}
 					if err != nil {
 						c.JSON(http.StatusBadRequest, gin.H{
 							"code":    400,
-							"message": "无效的应用ID",
+							"message": "更新应用失败",
+							"error":   err.Error(),
 						})
 						return
 					}
 
-					var req struct {
-						Version     string `json:"version" binding:"required"`
-						ChangelogMD string `json:"changelogMd"`
-					}
+					c.JSON(http.StatusOK, gin.H{
+						"code":    200,
+						"message": "应用更新成功",
+						"data":    app,
+					})
+				}
+				apps.PUT("/:id", updateApp)
+				apps.PATCH("/:id", updateApp)
 
-					if err := c.ShouldBindJSON(&req); err != nil {
+				apps.GET("/:id/signing-keys", func(c *gin.Context) {
+					appID, err := strconv.Atoi(c.Param("id"))
+					if err != nil {
 						c.JSON(http.StatusBadRequest, gin.H{
 							"code":    400,
-							"message": "请求参数错误",
-							"error":   err.Error(),
+							"message": "无效的应用ID",
 						})
 						return
 					}
 
-					// 创建版本
-					version, err := appService.CreateVersion(uint(appID), req.Version, req.ChangelogMD)
+					keys, err := signingService.GetSigningKeys(uint(appID))
 					if err != nil {
 						c.JSON(http.StatusInternalServerError, gin.H{
 							"code":    500,
-							"message": "创建版本失败",
+							"message": "获取签名密钥失败",
 							"error":   err.Error(),
 						})
 						return
 					}
 
-					// 清除相关缓存
-					cacheService.ClearApplicationCache()
-					cacheService.ClearVersionCache()
-
 					c.JSON(http.StatusOK, gin.H{
 						"code":    200,
-						"message": "版本创建成功",
-						"data":    version,
+						"message": "success",
+						"data":    keys,
 					})
 				})
 
-				// 版本列表API
-				apps.GET("/:id/versions", func(c *gin.Context) {
-					id := c.Param("id")
-					appID, err := strconv.Atoi(id)
+				apps.POST("/:id/signing-keys/rotate", func(c *gin.Context) {
+					appID, err := strconv.Atoi(c.Param("id"))
 					if err != nil {
 						c.JSON(http.StatusBadRequest, gin.H{
 							"code":    400,
@@ -418,61 +434,62 @@ func main() {
 						})
 						return
 					}
-					// 尝试从缓存获取
-					if cachedData, err := cacheService.GetVersionsCache(id); err == nil {
-						var versions []models.Version
-						if err := json.Unmarshal(cachedData, &versions); err == nil {
-							c.JSON(http.StatusOK, gin.H{
-								"code":    200,
-								"message": "success (from cache)",
-								"data":    versions,
+
+					// 请求体可选
+					var req models.RotateSigningKeyRequest
+					if c.Request.ContentLength > 0 {
+						if err := c.ShouldBindJSON(&req); err != nil {
+							c.JSON(http.StatusBadRequest, gin.H{
+								"code":    400,
+								"message": "请求参数错误",
+								"error":   err.Error(),
 							})
 							return
 						}
 					}
-					// 从数据库获取版本列表
-					versions, err := appService.GetVersions(uint(appID))
+
+					gracePeriod := config.SigningKeyGracePeriod()
+					if req.GracePeriodHours != nil {
+						if *req.GracePeriodHours < 0 || *req.GracePeriodHours > 720 {
+							c.JSON(http.StatusBadRequest, gin.H{
+								"code":    400,
+								"message": "宽限期必须在0-720小时之间",
+							})
+							return
+						}
+						gracePeriod = time.Duration(*req.GracePeriodHours) * time.Hour
+					}
+
+					// 轮换签名密钥
+					key, err := signingService.RotateSigningKey(uint(appID), gracePeriod)
 					if err != nil {
 						c.JSON(http.StatusInternalServerError, gin.H{
 							"code":    500,
-							"message": "获取版本列表失败",
+							"message": "轮换签名密钥失败",
 							"error":   err.Error(),
 						})
 						return
 					}
-					// 缓存数据
-					if data, err := json.Marshal(versions); err == nil {
-						cacheService.SetVersionsCache(id, data)
-					}
+
 					c.JSON(http.StatusOK, gin.H{
 						"code":    200,
-						"message": "success (from database)",
-						"data":    versions,
+						"message": "签名密钥轮换成功",
+						"data":    key,
 					})
 				})
-			}
 
-			// 会员管理API
-			members := protected.Group("/member")
-			{
 				// 会员等级API
-				members.GET("/levels", func(c *gin.Context) {
-					// 尝试从缓存获取
-					if cachedData, err := cacheService.GetMemberLevelsCache(); err == nil {
-						var levels []models.MemberLevel
-						if err := json.Unmarshal(cachedData, &levels); err == nil {
-							c.JSON(http.StatusOK, gin.H{
-								"code":    200,
-								"message": "success (from cache)",
-								"data": gin.H{
-									"levels": levels,
-								},
-							})
-							return
-						}
+				apps.GET("/:id/member-levels", func(c *gin.Context) {
+					appID, err := strconv.Atoi(c.Param("id"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的应用ID",
+						})
+						return
 					}
-					// 从数据库获取会员等级（暂时使用应用ID 1，后续需要从请求中获取）
-					levels, err := memberService.GetMemberLevels(1)
+
+					levels, err := memberService.GetMemberLevels(uint(appID))
 					if err != nil {
 						c.JSON(http.StatusInternalServerError, gin.H{
 							"code":    500,
@@ -481,24 +498,57 @@ func main() {
 						})
 						return
 					}
-					// 缓存数据
-					if data, err := json.Marshal(levels); err == nil {
-						cacheService.SetMemberLevelsCache(data)
+
+					c.JSON(http.StatusOK, gin.H{
+						"code":    200,
+						"message": "success",
+						"data": gin.H{
+							"levels": levels,
+						},
+					})
+				})
+
+				// 合并继承后的会员等级权限
+				apps.GET("/:id/member-levels/effective", func(c *gin.Context) {
+					appID, err := strconv.Atoi(c.Param("id"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的应用ID",
+						})
+						return
+					}
+
+					levels, err := memberService.GetEffectiveMemberLevels(uint(appID))
+					if err != nil {
+						c.JSON(http.StatusInternalServerError, gin.H{
+							"code":    500,
+							"message": "获取会员等级权限失败",
+							"error":   err.Error(),
+						})
+						return
 					}
+
 					c.JSON(http.StatusOK, gin.H{
 						"code":    200,
-						"message": "success (from database)",
+						"message": "success",
 						"data": gin.H{
 							"levels": levels,
 						},
 					})
 				})
 
-				members.PUT("/levels", func(c *gin.Context) {
-					var req struct {
-						Levels []gin.H `json:"levels" binding:"required"`
+				apps.PUT("/:id/member-levels", func(c *gin.Context) {
+					appID, err := strconv.Atoi(c.Param("id"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的应用ID",
+						})
+						return
 					}
 
+					var req models.UpdateMemberLevelsRequest
 					if err := c.ShouldBindJSON(&req); err != nil {
 						c.JSON(http.StatusBadRequest, gin.H{
 							"code":    400,
@@ -508,50 +558,52 @@ func main() {
 						return
 					}
 
-					// 转换数据格式
-					var levels []models.MemberLevel
-					for _, level := range req.Levels {
-						memberLevel := models.MemberLevel{
-							Name:        level["name"].(string),
-							Level:       int(level["level"].(float64)),
-							Permissions: level["permissions"].(string),
-						}
-						levels = append(levels, memberLevel)
+					// 整体替换该应用的会员等级
+					levels, err := memberService.UpdateMemberLevels(uint(appID), req.Levels)
+					var validationErr *services.PermissionValidationError
+					if errors.As(err, &validationErr) {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "权限配置不符合Schema",
+							"error":   err.Error(),
+							"errors":  validationErr.Errors,
+						})
+						return
 					}
-
-					// 更新会员等级
-					err := memberService.UpdateMemberLevels(levels)
 					if err != nil {
-						c.JSON(http.StatusInternalServerError, gin.H{
-							"code":    500,
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
 							"message": "更新会员等级失败",
 							"error":   err.Error(),
 						})
 						return
 					}
 
-					// 清除会员缓存
-					cacheService.ClearMemberCache()
-
 					c.JSON(http.StatusOK, gin.H{
 						"code":    200,
 						"message": "会员等级更新成功",
-						"data":    req.Levels,
+						"data": gin.H{
+							"levels": levels,
+						},
 					})
 				})
-			}
 
-			// 系统API
-			system := protected.Group("/system")
-			{
-				system.GET("/audit-logs", func(c *gin.Context) {
-					// 从数据库获取审计日志
-					logs, err := memberService.GetAuditLogs(100)
+				// 会员权限配置Schema API
+				apps.GET("/:id/permission-schema", func(c *gin.Context) {
+					appID, err := strconv.Atoi(c.Param("id"))
 					if err != nil {
-						c.JSON(http.StatusInternalServerError, gin.H{
-							"code":    500,
-							"message": "获取审计日志失败",
-							"error":   err.Error(),
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的应用ID",
+						})
+						return
+					}
+
+					schema, err := permissionSchemaService.GetSchema(uint(appID))
+					if err != nil {
+						c.JSON(http.StatusNotFound, gin.H{
+							"code":    404,
+							"message": "应用未注册权限配置Schema",
 						})
 						return
 					}
@@ -559,17 +611,46 @@ func main() {
 					c.JSON(http.StatusOK, gin.H{
 						"code":    200,
 						"message": "success",
-						"data":    logs,
+						"data":    schema,
 					})
 				})
 
-				// 缓存管理API
-				system.GET("/cache/stats", func(c *gin.Context) {
-					stats, err := cacheService.GetCacheStats()
+				apps.PUT("/:id/permission-schema", func(c *gin.Context) {
+					appID, err := strconv.Atoi(c.Param("id"))
 					if err != nil {
-						c.JSON(http.StatusInternalServerError, gin.H{
-							"code":    500,
-							"message": "获取缓存统计失败",
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的应用ID",
+						})
+						return
+					}
+
+					var req models.UpdatePermissionSchemaRequest
+					if err := c.ShouldBindJSON(&req); err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "请求参数错误",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					// 注册Schema，已有会员等级不符合时返回具体字段
+					schema, err := permissionSchemaService.SetSchema(uint(appID), req.Schema)
+					var validationErr *services.PermissionValidationError
+					if errors.As(err, &validationErr) {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "已有会员等级不符合Schema",
+							"error":   err.Error(),
+							"errors":  validationErr.Errors,
+						})
+						return
+					}
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "注册权限配置Schema失败",
 							"error":   err.Error(),
 						})
 						return
@@ -577,17 +658,25 @@ func main() {
 
 					c.JSON(http.StatusOK, gin.H{
 						"code":    200,
-						"message": "success",
-						"data":    stats,
+						"message": "权限配置Schema注册成功",
+						"data":    schema,
 					})
 				})
 
-				system.DELETE("/cache/clear", func(c *gin.Context) {
-					err := cacheService.ClearAllCache()
+				apps.DELETE("/:id/permission-schema", func(c *gin.Context) {
+					appID, err := strconv.Atoi(c.Param("id"))
 					if err != nil {
-						c.JSON(http.StatusInternalServerError, gin.H{
-							"code":    500,
-							"message": "清除缓存失败",
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的应用ID",
+						})
+						return
+					}
+
+					if err := permissionSchemaService.DeleteSchema(uint(appID)); err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "删除权限配置Schema失败",
 							"error":   err.Error(),
 						})
 						return
@@ -595,21 +684,1160 @@ func main() {
 
 					c.JSON(http.StatusOK, gin.H{
 						"code":    200,
-						"message": "缓存清除成功",
+						"message": "权限配置Schema删除成功",
 					})
 				})
 
-				// 性能监控API
-				system.GET("/performance/stats", func(c *gin.Context) {
-					stats := middleware.GetPerformanceStats()
+				// 终端用户会员订阅API
+				apps.GET("/:id/members", func(c *gin.Context) {
+					appID, err := strconv.Atoi(c.Param("id"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的应用ID",
+						})
+						return
+					}
+
+					// 分页参数，默认每页50条，最多200条
+					limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+					if err != nil || limit < 1 || limit > 200 {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "limit必须在1-200之间",
+						})
+						return
+					}
+					offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+					if err != nil || offset < 0 {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的offset",
+						})
+						return
+					}
+
+					members, total, err := appMemberService.GetMembers(uint(appID), c.Query("status"), limit, offset)
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "获取会员列表失败",
+							"error":   err.Error(),
+						})
+						return
+					}
+
 					c.JSON(http.StatusOK, gin.H{
 						"code":    200,
 						"message": "success",
-						"data":    stats,
+						"data": gin.H{
+							"members": members,
+							"total":   total,
+						},
 					})
 				})
 
-				system.POST("/performance/reset", func(c *gin.Context) {
+				apps.POST("/:id/members", func(c *gin.Context) {
+					appID, err := strconv.Atoi(c.Param("id"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的应用ID",
+						})
+						return
+					}
+
+					var req models.CreateAppMemberRequest
+					if err := c.ShouldBindJSON(&req); err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "请求参数错误",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					member, err := appMemberService.CreateMember(uint(appID), &req)
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "创建会员失败",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					c.JSON(http.StatusOK, gin.H{
+						"code":    200,
+						"message": "会员创建成功",
+						"data":    member,
+					})
+				})
+
+				apps.GET("/:id/members/:memberId", func(c *gin.Context) {
+					appID, err := strconv.Atoi(c.Param("id"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的应用ID",
+						})
+						return
+					}
+					memberID, err := strconv.Atoi(c.Param("memberId"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的会员ID",
+						})
+						return
+					}
+
+					member, err := appMemberService.GetMember(uint(appID), uint(memberID))
+					if err != nil {
+						c.JSON(http.StatusNotFound, gin.H{
+							"code":    404,
+							"message": "会员不存在",
+						})
+						return
+					}
+
+					c.JSON(http.StatusOK, gin.H{
+						"code":    200,
+						"message": "success",
+						"data":    member,
+					})
+				})
+
+				apps.PATCH("/:id/members/:memberId", func(c *gin.Context) {
+					appID, err := strconv.Atoi(c.Param("id"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的应用ID",
+						})
+						return
+					}
+					memberID, err := strconv.Atoi(c.Param("memberId"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的会员ID",
+						})
+						return
+					}
+
+					var req models.UpdateAppMemberRequest
+					if err := c.ShouldBindJSON(&req); err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "请求参数错误",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					member, err := appMemberService.UpdateMember(uint(appID), uint(memberID), &req)
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "更新会员失败",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					c.JSON(http.StatusOK, gin.H{
+						"code":    200,
+						"message": "会员更新成功",
+						"data":    member,
+					})
+				})
+
+				apps.DELETE("/:id/members/:memberId", func(c *gin.Context) {
+					appID, err := strconv.Atoi(c.Param("id"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的应用ID",
+						})
+						return
+					}
+					memberID, err := strconv.Atoi(c.Param("memberId"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的会员ID",
+						})
+						return
+					}
+
+					if err := appMemberService.DeleteMember(uint(appID), uint(memberID)); err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "删除会员失败",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					c.JSON(http.StatusOK, gin.H{
+						"code":    200,
+						"message": "会员删除成功",
+					})
+				})
+
+				apps.POST("/:id/api-keys/rotate", func(c *gin.Context) {
+					id := c.Param("id")
+					appID, err := strconv.Atoi(id)
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的应用ID",
+						})
+						return
+					}
+
+					// 请求体可选
+					var req models.RotateAPIKeyRequest
+					if c.Request.ContentLength > 0 {
+						if err := c.ShouldBindJSON(&req); err != nil {
+							c.JSON(http.StatusBadRequest, gin.H{
+								"code":    400,
+								"message": "请求参数错误",
+								"error":   err.Error(),
+							})
+							return
+						}
+					}
+
+					gracePeriod := config.APIKeyGracePeriod()
+					if req.GracePeriodHours != nil {
+						if *req.GracePeriodHours < 0 || *req.GracePeriodHours > 720 {
+							c.JSON(http.StatusBadRequest, gin.H{
+								"code":    400,
+								"message": "宽限期必须在0-720小时之间",
+							})
+							return
+						}
+						gracePeriod = time.Duration(*req.GracePeriodHours) * time.Hour
+					}
+
+					// 轮换API密钥
+					app, err := appService.RotateAPIKey(uint(appID), gracePeriod)
+					if errors.Is(err, services.ErrAppNotFound) {
+						c.JSON(http.StatusNotFound, gin.H{
+							"code":    404,
+							"message": "应用不存在",
+						})
+						return
+					}
+					if err != nil {
+						c.JSON(http.StatusInternalServerError, gin.H{
+							"code":    500,
+							"message": "轮换API密钥失败",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					c.JSON(http.StatusOK, gin.H{
+						"code":    200,
+						"message": "API密钥轮换成功",
+						"data": gin.H{
+							"apiKey":                  app.APIKey,
+							"previousApiKeyExpiresAt": app.PreviousAPIKeyExpiresAt,
+						},
+					})
+				})
+
+				// 命名API密钥管理
+				apps.GET("/:id/api-keys", func(c *gin.Context) {
+					id := c.Param("id")
+					appID, err := strconv.Atoi(id)
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的应用ID",
+						})
+						return
+					}
+
+					keys, err := apiKeyService.GetAPIKeys(uint(appID))
+					if err != nil {
+						c.JSON(http.StatusInternalServerError, gin.H{
+							"code":    500,
+							"message": "获取API密钥列表失败",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					c.JSON(http.StatusOK, gin.H{
+						"code":    200,
+						"message": "success",
+						"data":    keys,
+					})
+				})
+
+				apps.POST("/:id/api-keys", func(c *gin.Context) {
+					id := c.Param("id")
+					appID, err := strconv.Atoi(id)
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的应用ID",
+						})
+						return
+					}
+
+					var req models.CreateAPIKeyRequest
+					if err := c.ShouldBindJSON(&req); err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "请求参数错误",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					key, err := apiKeyService.CreateAPIKey(uint(appID), &req)
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "创建API密钥失败",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					c.JSON(http.StatusOK, gin.H{
+						"code":    200,
+						"message": "API密钥创建成功",
+						"data":    key,
+					})
+				})
+
+				apps.DELETE("/:id/api-keys/:keyId", func(c *gin.Context) {
+					appID, err := strconv.Atoi(c.Param("id"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的应用ID",
+						})
+						return
+					}
+					keyID, err := strconv.Atoi(c.Param("keyId"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的密钥ID",
+						})
+						return
+					}
+
+					key, err := apiKeyService.RevokeAPIKey(uint(appID), uint(keyID))
+					if err != nil {
+						c.JSON(http.StatusNotFound, gin.H{
+							"code":    404,
+							"message": "吊销API密钥失败",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					c.JSON(http.StatusOK, gin.H{
+						"code":    200,
+						"message": "API密钥已吊销",
+						"data":    key,
+					})
+				})
+
+				apps.DELETE("/:id", func(c *gin.Context) {
+					id := c.Param("id")
+					appID, err := strconv.Atoi(id)
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的应用ID",
+						})
+						return
+					}
+
+					// 删除应用
+					err = appService.DeleteApplication(uint(appID))
+					if err != nil {
+						c.JSON(http.StatusInternalServerError, gin.H{
+							"code":    500,
+							"message": "删除应用失败",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					// 清除相关缓存
+					cacheService.ClearApplicationCache()
+					cacheService.ClearVersionCache()
+					// 强制删除应用列表缓存
+					config.DeleteCache("apps:list")
+
+					c.JSON(http.StatusOK, gin.H{
+						"code":    200,
+						"message": "应用删除成功",
+						"data":    gin.H{"deletedId": appID},
+					})
+				})
+
+				apps.POST("/:id/versions", func(c *gin.Context) {
+					id := c.Param("id")
+					appID, err := strconv.Atoi(id)
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的应用ID",
+						})
+						return
+					}
+
+					var req models.CreateVersionRequest
+					if err := c.ShouldBindJSON(&req); err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "请求参数错误",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					// 创建版本
+					version, err := appService.CreateVersion(uint(appID), &req)
+					if errors.Is(err, services.ErrAppNotFound) {
+						c.JSON(http.StatusNotFound, gin.H{
+							"code":    404,
+							"message": "应用不存在",
+						})
+						return
+					}
+					if err != nil {
+						c.JSON(http.StatusInternalServerError, gin.H{
+							"code":    500,
+							"message": "创建版本失败",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					// 清除相关缓存
+					cacheService.ClearApplicationCache()
+					cacheService.ClearVersionCache()
+
+					c.JSON(http.StatusOK, gin.H{
+						"code":    200,
+						"message": "版本创建成功",
+						"data":    version,
+					})
+				})
+
+				apps.PATCH("/:id/versions/:versionId", func(c *gin.Context) {
+					appID, err := strconv.Atoi(c.Param("id"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的应用ID",
+						})
+						return
+					}
+					versionID, err := strconv.Atoi(c.Param("versionId"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的版本ID",
+						})
+						return
+					}
+
+					var req models.UpdateVersionRequest
+					if err := c.ShouldBindJSON(&req); err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "请求参数错误",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					// 更新版本
+					version, err := appService.UpdateVersion(uint(appID), uint(versionID), &req)
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "更新版本失败",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					c.JSON(http.StatusOK, gin.H{
+						"code":    200,
+						"message": "版本更新成功",
+						"data":    version,
+					})
+				})
+
+				apps.DELETE("/:id/versions/:versionId", func(c *gin.Context) {
+					appID, err := strconv.Atoi(c.Param("id"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的应用ID",
+						})
+						return
+					}
+					versionID, err := strconv.Atoi(c.Param("versionId"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的版本ID",
+						})
+						return
+					}
+
+					// 删除版本
+					if err := appService.DeleteVersion(uint(appID), uint(versionID)); err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "删除版本失败",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					c.JSON(http.StatusOK, gin.H{
+						"code":    200,
+						"message": "版本删除成功",
+						"data":    gin.H{"deletedId": versionID},
+					})
+				})
+
+				apps.POST("/:id/versions/:versionId/yank", func(c *gin.Context) {
+					appID, err := strconv.Atoi(c.Param("id"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的应用ID",
+						})
+						return
+					}
+					versionID, err := strconv.Atoi(c.Param("versionId"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的版本ID",
+						})
+						return
+					}
+
+					// 请求体可选
+					var req models.YankVersionRequest
+					if c.Request.ContentLength > 0 {
+						if err := c.ShouldBindJSON(&req); err != nil {
+							c.JSON(http.StatusBadRequest, gin.H{
+								"code":    400,
+								"message": "请求参数错误",
+								"error":   err.Error(),
+							})
+							return
+						}
+					}
+
+					// 撤回版本
+					version, err := appService.YankVersion(uint(appID), uint(versionID), req.Reason)
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "撤回版本失败",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					c.JSON(http.StatusOK, gin.H{
+						"code":    200,
+						"message": "版本已撤回",
+						"data":    version,
+					})
+				})
+
+				apps.DELETE("/:id/versions/:versionId/yank", func(c *gin.Context) {
+					appID, err := strconv.Atoi(c.Param("id"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的应用ID",
+						})
+						return
+					}
+					versionID, err := strconv.Atoi(c.Param("versionId"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的版本ID",
+						})
+						return
+					}
+
+					// 恢复已撤回的版本
+					version, err := appService.UnyankVersion(uint(appID), uint(versionID))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "恢复版本失败",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					c.JSON(http.StatusOK, gin.H{
+						"code":    200,
+						"message": "版本已恢复",
+						"data":    version,
+					})
+				})
+
+				apps.POST("/:id/versions/:versionId/publish", func(c *gin.Context) {
+					appID, err := strconv.Atoi(c.Param("id"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的应用ID",
+						})
+						return
+					}
+					versionID, err := strconv.Atoi(c.Param("versionId"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的版本ID",
+						})
+						return
+					}
+
+					// 立即发布
+					version, err := appService.PublishVersion(uint(appID), uint(versionID))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "发布版本失败",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					c.JSON(http.StatusOK, gin.H{
+						"code":    200,
+						"message": "版本发布成功",
+						"data":    version,
+					})
+				})
+
+				apps.POST("/:id/versions/:versionId/schedule", func(c *gin.Context) {
+					appID, err := strconv.Atoi(c.Param("id"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的应用ID",
+						})
+						return
+					}
+					versionID, err := strconv.Atoi(c.Param("versionId"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的版本ID",
+						})
+						return
+					}
+
+					var req models.ScheduleVersionRequest
+					if err := c.ShouldBindJSON(&req); err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "请求参数错误",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					// 设置定时发布
+					version, err := appService.ScheduleVersion(uint(appID), uint(versionID), req.PublishAt)
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "设置定时发布失败",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					c.JSON(http.StatusOK, gin.H{
+						"code":    200,
+						"message": "定时发布设置成功",
+						"data":    version,
+					})
+				})
+
+				// 构建产物API
+				apps.GET("/:id/versions/:versionId/artifacts", func(c *gin.Context) {
+					appID, err := strconv.Atoi(c.Param("id"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的应用ID",
+						})
+						return
+					}
+					versionID, err := strconv.Atoi(c.Param("versionId"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的版本ID",
+						})
+						return
+					}
+
+					artifacts, err := artifactService.GetArtifacts(uint(appID), uint(versionID))
+					if err != nil {
+						c.JSON(http.StatusInternalServerError, gin.H{
+							"code":    500,
+							"message": "获取构建产物失败",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					c.JSON(http.StatusOK, gin.H{
+						"code":    200,
+						"message": "success",
+						"data":    artifacts,
+					})
+				})
+
+				apps.POST("/:id/versions/:versionId/artifacts", func(c *gin.Context) {
+					appID, err := strconv.Atoi(c.Param("id"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的应用ID",
+						})
+						return
+					}
+					versionID, err := strconv.Atoi(c.Param("versionId"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的版本ID",
+						})
+						return
+					}
+
+					var req models.CreateArtifactRequest
+					if err := c.ShouldBindJSON(&req); err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "请求参数错误",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					// 添加构建产物
+					artifact, err := artifactService.CreateArtifact(uint(appID), uint(versionID), &req)
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "添加构建产物失败",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					c.JSON(http.StatusOK, gin.H{
+						"code":    200,
+						"message": "构建产物添加成功",
+						"data":    artifact,
+					})
+				})
+
+				// 上传构建产物文件，platform、arch 等表单字段需位于 file 之前
+				apps.POST("/:id/versions/:versionId/artifacts/upload", func(c *gin.Context) {
+					appID, err := strconv.Atoi(c.Param("id"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的应用ID",
+						})
+						return
+					}
+					versionID, err := strconv.Atoi(c.Param("versionId"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的版本ID",
+						})
+						return
+					}
+
+					reader, err := c.Request.MultipartReader()
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "请求必须为 multipart/form-data",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					// 逐个读取表单字段，文件内容直接流式写入存储
+					var req models.UploadArtifactRequest
+					var artifact *models.VersionArtifact
+					for artifact == nil {
+						part, err := reader.NextPart()
+						if err != nil {
+							c.JSON(http.StatusBadRequest, gin.H{
+								"code":    400,
+								"message": "缺少上传文件",
+							})
+							return
+						}
+
+						if part.FormName() == "file" {
+							if req.FileName == "" {
+								req.FileName = part.FileName()
+							}
+							artifact, err = artifactService.UploadArtifact(c.Request.Context(), uint(appID), uint(versionID), &req, part)
+							if err != nil {
+								c.JSON(http.StatusBadRequest, gin.H{
+									"code":    400,
+									"message": "上传构建产物失败",
+									"error":   err.Error(),
+								})
+								return
+							}
+							break
+						}
+
+						value, _ := io.ReadAll(io.LimitReader(part, 1024))
+						switch part.FormName() {
+						case "platform":
+							req.Platform = string(value)
+						case "arch":
+							req.Arch = string(value)
+						case "fileName":
+							req.FileName = string(value)
+						case "sha256":
+							req.SHA256 = string(value)
+						case "edSignature":
+							req.EdSignature = string(value)
+						case "minimumSystemVersion":
+							req.MinimumSystemVersion = string(value)
+						case "updaterSignature":
+							req.UpdaterSignature = string(value)
+						}
+					}
+
+					c.JSON(http.StatusOK, gin.H{
+						"code":    200,
+						"message": "构建产物上传成功",
+						"data":    artifact,
+					})
+				})
+
+				apps.DELETE("/:id/versions/:versionId/artifacts/:artifactId", func(c *gin.Context) {
+					appID, err := strconv.Atoi(c.Param("id"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的应用ID",
+						})
+						return
+					}
+					versionID, err := strconv.Atoi(c.Param("versionId"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的版本ID",
+						})
+						return
+					}
+					artifactID, err := strconv.Atoi(c.Param("artifactId"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的构建产物ID",
+						})
+						return
+					}
+
+					// 删除构建产物
+					if err := artifactService.DeleteArtifact(uint(appID), uint(versionID), uint(artifactID)); err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "删除构建产物失败",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					c.JSON(http.StatusOK, gin.H{
+						"code":    200,
+						"message": "构建产物删除成功",
+						"data":    gin.H{"deletedId": artifactID},
+					})
+				})
+
+				apps.POST("/:id/versions/:versionId/promote", func(c *gin.Context) {
+					appID, err := strconv.Atoi(c.Param("id"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的应用ID",
+						})
+						return
+					}
+					versionID, err := strconv.Atoi(c.Param("versionId"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的版本ID",
+						})
+						return
+					}
+
+					var req models.PromoteVersionRequest
+					if err := c.ShouldBindJSON(&req); err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "请求参数错误",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					// 提升版本渠道
+					version, err := appService.PromoteVersion(uint(appID), uint(versionID), req.Channel)
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "提升版本渠道失败",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					c.JSON(http.StatusOK, gin.H{
+						"code":    200,
+						"message": "版本渠道提升成功",
+						"data":    version,
+					})
+				})
+
+				apps.PATCH("/:id/versions/:versionId/rollout", func(c *gin.Context) {
+					appID, err := strconv.Atoi(c.Param("id"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的应用ID",
+						})
+						return
+					}
+					versionID, err := strconv.Atoi(c.Param("versionId"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的版本ID",
+						})
+						return
+					}
+
+					var req models.UpdateRolloutRequest
+					if err := c.ShouldBindJSON(&req); err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "请求参数错误",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					// 调整灰度发布
+					version, err := appService.UpdateRollout(uint(appID), uint(versionID), &req)
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "调整灰度发布失败",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					c.JSON(http.StatusOK, gin.H{
+						"code":    200,
+						"message": "灰度发布已更新",
+						"data":    version,
+					})
+				})
+
+				// 对比两个版本之间的所有版本及合并更新日志
+				apps.GET("/:id/versions/compare", func(c *gin.Context) {
+					appID, err := strconv.Atoi(c.Param("id"))
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的应用ID",
+						})
+						return
+					}
+
+					var req models.CompareVersionsRequest
+					if err := c.ShouldBindQuery(&req); err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "请求参数错误",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					comparison, err := updateService.CompareVersions(uint(appID), &req)
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "版本对比失败",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					c.JSON(http.StatusOK, gin.H{
+						"code":    200,
+						"message": "success",
+						"data":    comparison,
+					})
+				})
+
+				// 版本列表API
+				apps.GET("/:id/versions", func(c *gin.Context) {
+					id := c.Param("id")
+					appID, err := strconv.Atoi(id)
+					if err != nil {
+						c.JSON(http.StatusBadRequest, gin.H{
+							"code":    400,
+							"message": "无效的应用ID",
+						})
+						return
+					}
+					// 尝试从缓存获取
+					if cachedData, err := cacheService.GetVersionsCache(id); err == nil {
+						var versions []models.Version
+						if err := json.Unmarshal(cachedData, &versions); err == nil {
+							c.JSON(http.StatusOK, gin.H{
+								"code":    200,
+								"message": "success (from cache)",
+								"data":    versions,
+							})
+							return
+						}
+					}
+					// 从数据库获取版本列表
+					versions, err := appService.GetVersions(uint(appID))
+					if err != nil {
+						c.JSON(http.StatusInternalServerError, gin.H{
+							"code":    500,
+							"message": "获取版本列表失败",
+							"error":   err.Error(),
+						})
+						return
+					}
+					// 缓存数据
+					if data, err := json.Marshal(versions); err == nil {
+						cacheService.SetVersionsCache(id, data)
+					}
+					c.JSON(http.StatusOK, gin.H{
+						"code":    200,
+						"message": "success (from database)",
+						"data":    versions,
+					})
+				})
+			}
+
+			// 系统API
+			system := protected.Group("/system")
+			{
+				system.GET("/audit-logs", func(c *gin.Context) {
+					// 从数据库获取审计日志
+					logs, err := memberService.GetAuditLogs(100)
+					if err != nil {
+						c.JSON(http.StatusInternalServerError, gin.H{
+							"code":    500,
+							"message": "获取审计日志失败",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					c.JSON(http.StatusOK, gin.H{
+						"code":    200,
+						"message": "success",
+						"data":    logs,
+					})
+				})
+
+				// 缓存管理API
+				system.GET("/cache/stats", func(c *gin.Context) {
+					stats, err := cacheService.GetCacheStats()
+					if err != nil {
+						c.JSON(http.StatusInternalServerError, gin.H{
+							"code":    500,
+							"message": "获取缓存统计失败",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					c.JSON(http.StatusOK, gin.H{
+						"code":    200,
+						"message": "success",
+						"data":    stats,
+					})
+				})
+
+				system.DELETE("/cache/clear", func(c *gin.Context) {
+					err := cacheService.ClearAllCache()
+					if err != nil {
+						c.JSON(http.StatusInternalServerError, gin.H{
+							"code":    500,
+							"message": "清除缓存失败",
+							"error":   err.Error(),
+						})
+						return
+					}
+
+					c.JSON(http.StatusOK, gin.H{
+						"code":    200,
+						"message": "缓存清除成功",
+					})
+				})
+
+				// 性能监控API
+				system.GET("/performance/stats", func(c *gin.Context) {
+					stats := middleware.GetPerformanceStats()
+					c.JSON(http.StatusOK, gin.H{
+						"code":    200,
+						"message": "success",
+						"data":    stats,
+					})
+				})
+
+				system.POST("/performance/reset", func(c *gin.Context) {
 					middleware.ResetPerformanceStats()
 					c.JSON(http.StatusOK, gin.H{
 						"code":    200,
@@ -617,23 +1845,237 @@ func main() {
 					})
 				})
 			}
-		}
-	}
+		}
+	}
+
+	// 外部API（需要API密钥验证）
+	external := r.Group("/api/v1/external")
+	external.Use(middleware.APIKeyMiddleware())
+	{
+		// 获取应用最新版本
+		external.GET("/version", middleware.RequireScope(models.ScopeVersionRead), func(c *gin.Context) {
+			app := c.MustGet("app").(*models.Application)
+
+			// 发布渠道，默认为 stable
+			channel := c.DefaultQuery("channel", models.ChannelStable)
+			if models.ChannelRank(channel) < 0 {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"code":    400,
+					"message": "无效的发布渠道",
+				})
+				return
+			}
+
+			// 更新日志格式，默认为 html
+			format := c.DefaultQuery("format", utils.ChangelogFormatHTML)
+			if !utils.IsValidChangelogFormat(format) {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"code":    400,
+					"message": "无效的更新日志格式",
+				})
+				return
+			}
+
+			// 更新日志分类，为空时返回全部内容
+			category := c.Query("category")
+			if category != "" && !models.IsValidChangelogCategory(category) {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"code":    400,
+					"message": "无效的更新日志分类",
+				})
+				return
+			}
+
+			// 按语义化版本优先级获取客户端可获取的最新版本，灰度中的版本按客户端ID分桶
+			clientID := c.Query("clientId")
+			if clientID == "" {
+				clientID = c.GetHeader("X-Client-ID")
+			}
+			latestVersion, err := updateService.GetLatestForClient(app.ID, channel, clientID)
+			if err != nil {
+				c.JSON(http.StatusNotFound, gin.H{
+					"code":    404,
+					"message": "未找到版本信息",
+				})
+				return
+			}
+
+			// 返回与客户端平台匹配的构建产物
+			artifact, err := artifactService.FindArtifact(latestVersion.ID, c.Query("platform"), c.Query("arch"))
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"code":    500,
+					"message": "获取构建产物失败",
+				})
+				return
+			}
+
+			// 结构化更新日志条目，指定分类时只保留该分类并重新生成更新日志
+			entries, err := changelogService.GetEntries(latestVersion.ID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"code":    500,
+					"message": "获取更新日志失败",
+				})
+				return
+			}
+			changelogMD, changelogHTML := latestVersion.ChangelogMD, latestVersion.ChangelogHTML
+			if category != "" {
+				entries = models.FilterChangelogEntries(entries, category)
+				changelogMD = models.ChangelogMarkdown(entries)
+				changelogHTML = utils.RenderMarkdown(changelogMD)
+			}
+
+			// 按 lang 参数或 Accept-Language 选择更新日志语言，没有匹配的语言时使用默认更新日志
+			// 结构化条目只有默认语言，指定分类时不做语言选择
+			c.Header("Vary", "Accept-Language")
+			var localized *models.LocalizedChangelog
+			if category == "" {
+				chain := utils.LocaleFallbackChain(utils.PreferredLocales(c.Query("lang"), c.GetHeader("Accept-Language")))
+				localized, err = changelogService.ResolveChangelog(latestVersion.ID, chain)
+				if err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{
+						"code":    500,
+						"message": "获取更新日志失败",
+					})
+					return
+				}
+			}
+			if localized != nil {
+				changelogMD, changelogHTML = localized.ChangelogMD, localized.ChangelogHTML
+				c.Header("Content-Language", localized.Locale)
+			}
+
+			data := gin.H{
+				"appName":         app.Name,
+				"status":          app.Status,
+				"version":         latestVersion.Version,
+				"channel":         latestVersion.Channel,
+				"changelog":       utils.FormatChangelog(changelogMD, changelogHTML, format),
+				"changelogFormat": format,
+				"updatedAt":       latestVersion.ReleasedAt(),
+			}
+			if localized != nil {
+				data["locale"] = localized.Locale
+			}
+			if len(entries) > 0 {
+				data["entries"] = entries
+			}
+			if artifact != nil {
+				artifact.URL = utils.AbsoluteURL(c.Request, artifact.URL)
+				data["artifact"] = artifact
+			}
+			// 已停用的应用附带停用说明
+			if app.Status == models.AppStatusDeprecated {
+				data["sunsetMessage"] = middleware.StatusNotice(app)
+			}
+
+			// 对响应数据签名，客户端可用应用公钥校验
+			signature, err := signingService.Sign(app.ID, data)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"code":    500,
+					"message": "签名失败",
+				})
+				return
+			}
 
-	// 外部API（需要API密钥验证）
-	external := r.Group("/api/v1/external")
-	external.Use(middleware.APIKeyMiddleware())
-	{
-		// 获取应用最新版本
-		external.GET("/version", func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{
+				"code":      200,
+				"message":   "success",
+				"data":      data,
+				"signature": signature,
+			})
+		})
+
+		// 客户端更新检查
+		external.GET("/update-check", middleware.RequireScope(models.ScopeVersionRead), func(c *gin.Context) {
 			app := c.MustGet("app").(*models.Application)
 
-			// 获取最新版本
-			var latestVersion models.Version
-			if err := config.DB.Where("app_id = ?", app.ID).Order("created_at DESC").First(&latestVersion).Error; err != nil {
-				c.JSON(http.StatusNotFound, gin.H{
-					"code":    404,
-					"message": "未找到版本信息",
+			var req models.UpdateCheckRequest
+			if err := c.ShouldBindQuery(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"code":    400,
+					"message": "请求参数错误",
+					"error":   err.Error(),
+				})
+				return
+			}
+			if req.ClientID == "" {
+				req.ClientID = c.GetHeader("X-Client-ID")
+			}
+
+			result, err := updateService.CheckUpdate(app, &req)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"code":    400,
+					"message": "检查更新失败",
+					"error":   err.Error(),
+				})
+				return
+			}
+			if result.Artifact != nil {
+				result.Artifact.URL = utils.AbsoluteURL(c.Request, result.Artifact.URL)
+			}
+
+			// 对响应数据签名，客户端可用应用公钥校验
+			signature, err := signingService.Sign(app.ID, result)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"code":    500,
+					"message": "签名失败",
+				})
+				return
+			}
+
+			c.JSON(http.StatusOK, gin.H{
+				"code":      200,
+				"message":   "success",
+				"data":      result,
+				"signature": signature,
+			})
+		})
+
+		// 对比两个版本之间的所有版本及合并更新日志，用于跨多个版本升级时展示变更
+		external.GET("/versions/compare", middleware.RequireScope(models.ScopeVersionRead), func(c *gin.Context) {
+			app := c.MustGet("app").(*models.Application)
+
+			var req models.CompareVersionsRequest
+			if err := c.ShouldBindQuery(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"code":    400,
+					"message": "请求参数错误",
+					"error":   err.Error(),
+				})
+				return
+			}
+
+			comparison, err := updateService.CompareVersions(app.ID, &req)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"code":    400,
+					"message": "版本对比失败",
+					"error":   err.Error(),
+				})
+				return
+			}
+
+			c.JSON(http.StatusOK, gin.H{
+				"code":    200,
+				"message": "success",
+				"data":    comparison,
+			})
+		})
+
+		// 获取应用的签名公钥，密钥轮换后的宽限期内同时返回新旧公钥
+		external.GET("/signing-keys", middleware.RequireScope(models.ScopeVersionRead), func(c *gin.Context) {
+			app := c.MustGet("app").(*models.Application)
+
+			keys, err := signingService.GetPublicKeys(app.ID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"code":    500,
+					"message": "获取签名公钥失败",
 				})
 				return
 			}
@@ -642,21 +2084,19 @@ func main() {
 				"code":    200,
 				"message": "success",
 				"data": gin.H{
-					"appName":   app.Name,
-					"version":   latestVersion.Version,
-					"changelog": latestVersion.ChangelogHTML,
-					"updatedAt": latestVersion.CreatedAt,
+					"appName": app.Name,
+					"keys":    keys,
 				},
 			})
 		})
 
 		// 获取应用会员等级信息
-		external.GET("/member-levels", func(c *gin.Context) {
+		external.GET("/member-levels", middleware.RequireScope(models.ScopeMemberLevelsRead), func(c *gin.Context) {
 			app := c.MustGet("app").(*models.Application)
 
 			// 获取会员等级列表
-			var memberLevels []models.MemberLevel
-			if err := config.DB.Where("app_id = ?", app.ID).Order("level ASC").Find(&memberLevels).Error; err != nil {
+			memberLevels, err := memberService.GetMemberLevels(app.ID)
+			if err != nil {
 				c.JSON(http.StatusInternalServerError, gin.H{
 					"code":    500,
 					"message": "获取会员等级失败",
@@ -673,8 +2113,426 @@ func main() {
 				},
 			})
 		})
+
+		// 获取合并继承后的会员等级权限
+		external.GET("/member-levels/effective", middleware.RequireScope(models.ScopeMemberLevelsRead), func(c *gin.Context) {
+			app := c.MustGet("app").(*models.Application)
+
+			levels, err := memberService.GetEffectiveMemberLevels(app.ID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"code":    500,
+					"message": "获取会员等级权限失败",
+				})
+				return
+			}
+
+			c.JSON(http.StatusOK, gin.H{
+				"code":    200,
+				"message": "success",
+				"data": gin.H{
+					"appName":      app.Name,
+					"memberLevels": levels,
+				},
+			})
+		})
+
+		// 获取会员权限配置的JSON Schema，供客户端SDK校验和生成类型
+		external.GET("/permission-schema", middleware.RequireScope(models.ScopeMemberLevelsRead), func(c *gin.Context) {
+			app := c.MustGet("app").(*models.Application)
+
+			schema, err := permissionSchemaService.GetSchema(app.ID)
+			if err != nil {
+				c.JSON(http.StatusNotFound, gin.H{
+					"code":    404,
+					"message": "应用未注册权限配置Schema",
+				})
+				return
+			}
+
+			c.JSON(http.StatusOK, gin.H{
+				"code":    200,
+				"message": "success",
+				"data": gin.H{
+					"appName":   app.Name,
+					"schema":    schema.Schema,
+					"updatedAt": schema.UpdatedAt,
+				},
+			})
+		})
+
+		// 获取终端用户当前的会员等级及合并继承后的权限配置
+		external.GET("/members/:externalUserId", middleware.RequireScope(models.ScopeMembersRead), func(c *gin.Context) {
+			app := c.MustGet("app").(*models.Application)
+
+			membership, err := appMemberService.GetMembership(app.ID, c.Param("externalUserId"))
+			if errors.Is(err, services.ErrMemberNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{
+					"code":    404,
+					"message": "该用户没有会员订阅",
+				})
+				return
+			}
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"code":    500,
+					"message": "获取会员信息失败",
+				})
+				return
+			}
+
+			// 对响应数据签名，客户端可用应用公钥校验会员信息未被篡改
+			signature, err := signingService.Sign(app.ID, membership)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"code":    500,
+					"message": "签名失败",
+				})
+				return
+			}
+
+			c.JSON(http.StatusOK, gin.H{
+				"code":      200,
+				"message":   "success",
+				"data":      membership,
+				"signature": signature,
+			})
+		})
+
+		// 检查终端用户的权益，由服务端根据会员等级判断是否允许及额度
+		external.POST("/entitlements/check", middleware.RequireScope(models.ScopeMembersRead), func(c *gin.Context) {
+			app := c.MustGet("app").(*models.Application)
+
+			var req models.EntitlementCheckRequest
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"code":    400,
+					"message": "请求参数错误",
+					"error":   err.Error(),
+				})
+				return
+			}
+
+			check, err := appMemberService.CheckEntitlements(app.ID, &req)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"code":    400,
+					"message": "权益检查失败",
+					"error":   err.Error(),
+				})
+				return
+			}
+
+			// 对响应数据签名，客户端可用应用公钥校验检查结果未被篡改
+			signature, err := signingService.Sign(app.ID, check)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"code":    500,
+					"message": "签名失败",
+				})
+				return
+			}
+
+			c.JSON(http.StatusOK, gin.H{
+				"code":      200,
+				"message":   "success",
+				"data":      check,
+				"signature": signature,
+			})
+		})
+	}
+
+	// 构建产物下载，使用 X-API-Key 请求头或更新源签发的限时下载令牌认证
+	downloads := r.Group("/api/v1/external")
+	downloads.Use(middleware.DownloadMiddleware())
+	{
+		// 下载托管的构建产物，支持 Range 断点续传
+		downloads.GET("/download/:artifactId", middleware.RequireScope(models.ScopeVersionRead), func(c *gin.Context) {
+			app := c.MustGet("app").(*models.Application)
+
+			artifactID, err := strconv.Atoi(c.Param("artifactId"))
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"code":    400,
+					"message": "无效的构建产物ID",
+				})
+				return
+			}
+
+			artifact, err := artifactService.GetDownloadableArtifact(app.ID, uint(artifactID))
+			if err != nil {
+				c.JSON(http.StatusNotFound, gin.H{
+					"code":    404,
+					"message": "构建产物不存在",
+				})
+				return
+			}
+
+			serveArtifact(c, artifact)
+		})
+	}
+
+	// 供更新框架使用的外部API，允许通过 apiKey 查询参数传递密钥
+	updaterFeeds := r.Group("/api/v1/external")
+	updaterFeeds.Use(middleware.APIKeyQueryMiddleware())
+	{
+		// Sparkle appcast
+		updaterFeeds.GET("/appcast.xml", middleware.RequireScope(models.ScopeVersionRead), func(c *gin.Context) {
+			app := c.MustGet("app").(*models.Application)
+
+			clientID := c.Query("clientId")
+			if clientID == "" {
+				clientID = c.GetHeader("X-Client-ID")
+			}
+			releases, err := feedService.GetReleases(app.ID, c.DefaultQuery("channel", models.ChannelStable),
+				clientID, "macos", c.Query("arch"), 0)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"code":    400,
+					"message": "获取版本列表失败",
+					"error":   err.Error(),
+				})
+				return
+			}
+
+			resolveFeedURLs(c, app, releases)
+
+			data, err := feeds.Sparkle(&feeds.Feed{
+				Title:       app.Name,
+				Description: app.Description,
+				Link:        utils.AbsoluteURL(c.Request, "/"),
+				Releases:    releases,
+			})
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"code":    500,
+					"message": "生成 appcast 失败",
+				})
+				return
+			}
+
+			c.Data(http.StatusOK, feeds.SparkleContentType, data)
+		})
+
+		// Electron（Squirrel.Mac、Squirrel.Windows）和 Tauri 更新源
+		// Squirrel.Windows 会在地址后追加 /RELEASES，并从同一目录下载安装包
+		updaterFeed := func(c *gin.Context) {
+			app := c.MustGet("app").(*models.Application)
+
+			platforms := map[string]string{
+				"squirrel-mac":     "macos",
+				"squirrel-windows": "windows",
+				"tauri":            "",
+			}
+			format := c.Param("format")
+			platform, ok := platforms[format]
+			if !ok {
+				c.JSON(http.StatusNotFound, gin.H{
+					"code":    404,
+					"message": "不支持的更新源格式",
+				})
+				return
+			}
+
+			clientID := c.Query("clientId")
+			if clientID == "" {
+				clientID = c.GetHeader("X-Client-ID")
+			}
+			releases, err := feedService.GetReleases(app.ID, c.DefaultQuery("channel", models.ChannelStable),
+				clientID, platform, c.Query("arch"), 0)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"code":    400,
+					"message": "获取版本列表失败",
+					"error":   err.Error(),
+				})
+				return
+			}
+			resolveFeedURLs(c, app, releases)
+
+			feed := &feeds.Feed{Title: app.Name, Description: app.Description, Releases: releases}
+			switch format {
+			case "squirrel-mac":
+				data, err := feeds.SquirrelMac(feed)
+				if err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{
+						"code":    500,
+						"message": "生成更新源失败",
+					})
+					return
+				}
+				c.Data(http.StatusOK, feeds.SquirrelMacContentType, data)
+			case "squirrel-windows":
+				c.Data(http.StatusOK, feeds.SquirrelWindowsContentType, feeds.SquirrelWindows(feed))
+			case "tauri":
+				data, err := feeds.Tauri(feed)
+				if errors.Is(err, feeds.ErrNoRelease) {
+					// Tauri 将 204 视为没有可用更新
+					c.Status(http.StatusNoContent)
+					return
+				}
+				if err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{
+						"code":    500,
+						"message": "生成更新源失败",
+					})
+					return
+				}
+				c.Data(http.StatusOK, feeds.TauriContentType, data)
+			}
+		}
+		updaterFeeds.GET("/feeds/:format", middleware.RequireScope(models.ScopeVersionRead), updaterFeed)
+		updaterFeeds.GET("/feeds/:format/:file", middleware.RequireScope(models.ScopeVersionRead), func(c *gin.Context) {
+			if c.Param("file") == "RELEASES" {
+				updaterFeed(c)
+				return
+			}
+
+			// Squirrel.Windows 按 RELEASES 中的文件名相对更新源地址下载安装包
+			if c.Param("format") != "squirrel-windows" {
+				c.JSON(http.StatusNotFound, gin.H{
+					"code":    404,
+					"message": "不支持的更新源格式",
+				})
+				return
+			}
+
+			app := c.MustGet("app").(*models.Application)
+			clientID := c.Query("clientId")
+			if clientID == "" {
+				clientID = c.GetHeader("X-Client-ID")
+			}
+			releases, err := feedService.GetReleases(app.ID, c.DefaultQuery("channel", models.ChannelStable),
+				clientID, "windows", c.Query("arch"), 0)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"code":    400,
+					"message": "获取版本列表失败",
+					"error":   err.Error(),
+				})
+				return
+			}
+
+			feed := &feeds.Feed{Title: app.Name, Releases: releases}
+			for i := range releases {
+				if releases[i].Artifact != nil && feeds.SquirrelWindowsFileName(feed, &releases[i]) == c.Param("file") {
+					serveArtifact(c, releases[i].Artifact)
+					return
+				}
+			}
+			c.JSON(http.StatusNotFound, gin.H{
+				"code":    404,
+				"message": "构建产物不存在",
+			})
+		})
+	}
+
+	// 公开的更新日志订阅源，无需认证，应用开启后才可访问
+	public := r.Group("/api/v1/public")
+	{
+		publicFeed := func(render func(*feeds.Feed) ([]byte, error), contentType string) gin.HandlerFunc {
+			return func(c *gin.Context) {
+				appID, err := strconv.Atoi(c.Param("id"))
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{
+						"code":    400,
+						"message": "无效的应用ID",
+					})
+					return
+				}
+
+				app, releases, err := feedService.GetPublicReleases(uint(appID))
+				if err != nil {
+					c.JSON(http.StatusNotFound, gin.H{
+						"code":    404,
+						"message": "订阅源不存在",
+					})
+					return
+				}
+
+				feed := &feeds.Feed{
+					ID:          fmt.Sprintf("urn:app-management:apps:%d", app.ID),
+					Title:       app.Name,
+					Description: app.Description,
+					Author:      app.Name,
+					Link:        utils.AbsoluteURL(c.Request, "/"),
+					SelfLink:    utils.AbsoluteURL(c.Request, c.Request.URL.Path),
+					Releases:    releases,
+				}
+				data, err := render(feed)
+				if err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{
+						"code":    500,
+						"message": "生成订阅源失败",
+					})
+					return
+				}
+
+				lastModified := feed.LastModified()
+				if app.UpdatedAt.After(lastModified) {
+					lastModified = app.UpdatedAt
+				}
+				sum := sha256.Sum256(data)
+
+				// ServeContent 根据 ETag 和 Last-Modified 处理条件请求，未变化时返回 304
+				c.Header("Content-Type", contentType)
+				c.Header("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+				c.Header("Cache-Control", "public, max-age=300")
+				http.ServeContent(c.Writer, c.Request, "", lastModified, bytes.NewReader(data))
+			}
+		}
+		public.GET("/apps/:id/feed.atom", publicFeed(feeds.Atom, feeds.AtomContentType))
+		public.GET("/apps/:id/feed.rss", publicFeed(feeds.RSS, feeds.RSSContentType))
 	}
 
 	log.Println("服务器启动在端口 8080...")
 	r.Run(":8080")
 }
+
+// serveArtifact 输出构建产物：外部地址的产物直接跳转，托管文件支持 Range 断点续传
+func serveArtifact(c *gin.Context, artifact *models.VersionArtifact) {
+	if !artifact.IsHosted() {
+		c.Redirect(http.StatusFound, artifact.URL)
+		return
+	}
+
+	file, object, err := config.Storage.Open(c.Request.Context(), artifact.StorageKey)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    404,
+			"message": "构建产物文件不存在",
+		})
+		return
+	}
+	defer file.Close()
+
+	c.Header("Content-Type", "application/octet-stream")
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": artifact.FileName}))
+	if artifact.SHA256 != "" {
+		c.Header("ETag", `"`+artifact.SHA256+`"`)
+		c.Header("X-Checksum-SHA256", artifact.SHA256)
+	}
+	http.ServeContent(c.Writer, c.Request, artifact.FileName, object.ModTime, file)
+}
+
+// resolveFeedURLs 将更新源中的产物地址转换为完整地址
+// 托管产物的下载地址附带限时下载令牌，不回显请求中的API密钥
+func resolveFeedURLs(c *gin.Context, app *models.Application, releases []feeds.Release) {
+	secret := config.DownloadTokenSecret()
+	expiresAt := time.Now().Add(config.DownloadTokenTTL())
+	resolve := func(artifact *models.VersionArtifact) {
+		artifact.URL = utils.AbsoluteURL(c.Request, artifact.URL)
+		if artifact.IsHosted() {
+			artifact.URL += "?token=" + utils.SignDownloadToken(secret, app.ID, artifact.ID, expiresAt)
+		}
+	}
+
+	for i := range releases {
+		if releases[i].Artifact != nil {
+			resolve(releases[i].Artifact)
+		}
+		for j := range releases[i].Artifacts {
+			resolve(&releases[i].Artifacts[j])
+		}
+	}
+}
@@ -3,16 +3,46 @@ package middleware
 import (
 	"app_management/config"
 	"app_management/models"
+	"app_management/utils"
+	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-// APIKeyMiddleware API密钥验证中间件
+// 请求所使用的API密钥类型
+const (
+	APIKeyTypeCurrent  = "current"
+	APIKeyTypePrevious = "previous"
+	APIKeyTypeScoped   = "scoped"
+)
+
+// 默认的状态提示，应用未配置状态说明时使用
+const (
+	defaultMaintenanceNotice = "应用正在维护中，请稍后再试"
+	defaultSunsetMessage     = "该应用已停止维护，请尽快迁移"
+)
+
+// APIKeyMiddleware API密钥验证中间件，从 X-API-Key 请求头读取密钥
 func APIKeyMiddleware() gin.HandlerFunc {
+	return apiKeyMiddleware(false)
+}
+
+// APIKeyQueryMiddleware 同 APIKeyMiddleware，但也接受 apiKey 查询参数
+// 仅用于无法自定义请求头的更新框架（如 Sparkle）拉取更新源
+func APIKeyQueryMiddleware() gin.HandlerFunc {
+	return apiKeyMiddleware(true)
+}
+
+func apiKeyMiddleware(allowQuery bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 从请求头获取API密钥
 		apiKey := c.GetHeader("X-API-Key")
+		if apiKey == "" && allowQuery {
+			apiKey = c.Query("apiKey")
+		}
 		if apiKey == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"code":    401,
@@ -23,8 +53,8 @@ func APIKeyMiddleware() gin.HandlerFunc {
 		}
 
 		// 验证API密钥
-		var app models.Application
-		if err := config.DB.Where("api_key = ? AND status = ?", apiKey, "active").First(&app).Error; err != nil {
+		app, keyType, scopes, err := resolveAPIKey(apiKey)
+		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"code":    401,
 				"message": "无效的API密钥",
@@ -33,8 +63,180 @@ func APIKeyMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		if !checkAppStatus(c, app) {
+			return
+		}
+
+		// 标明本次请求使用的密钥类型
+		if keyType == APIKeyTypePrevious {
+			c.Header("X-API-Key-Expires-At", app.PreviousAPIKeyExpiresAt.Format(time.RFC3339))
+		}
+		c.Header("X-API-Key-Status", keyType)
+
 		// 将应用信息存储到上下文中
+		c.Set("app", app)
+		c.Set("apiKeyType", keyType)
+		c.Set("apiKeyScopes", scopes)
+		c.Next()
+	}
+}
+
+// DownloadMiddleware 构建产物下载认证中间件，接受 X-API-Key 请求头或更新源签发的限时 token 参数
+// 令牌只授予下载所属构建产物的 version:read 权限
+func DownloadMiddleware() gin.HandlerFunc {
+	headerAuth := apiKeyMiddleware(false)
+	return func(c *gin.Context) {
+		token := c.Query("token")
+		if token == "" {
+			headerAuth(c)
+			return
+		}
+
+		artifactID, err := strconv.ParseUint(c.Param("artifactId"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    400,
+				"message": "无效的构建产物ID",
+			})
+			c.Abort()
+			return
+		}
+
+		appID, err := utils.ParseDownloadToken(config.DownloadTokenSecret(), token, uint(artifactID), time.Now())
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"code":    401,
+				"message": err.Error(),
+			})
+			c.Abort()
+			return
+		}
+
+		var app models.Application
+		if err := config.DB.First(&app, appID).Error; err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"code":    401,
+				"message": "无效的下载令牌",
+			})
+			c.Abort()
+			return
+		}
+		if !checkAppStatus(c, &app) {
+			return
+		}
+
 		c.Set("app", &app)
+		c.Set("apiKeyScopes", models.ScopeList{models.ScopeVersionRead})
 		c.Next()
 	}
-} 
\ No newline at end of file
+}
+
+// checkAppStatus 根据应用状态拦截或标记请求，请求被拦截时返回 false
+func checkAppStatus(c *gin.Context, app *models.Application) bool {
+	switch app.Status {
+	case models.AppStatusMaintenance:
+		// 维护中的应用不对外提供服务
+		c.Header("Retry-After", "600")
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"code":    503,
+			"message": "应用维护中",
+			"data": gin.H{
+				"status": app.Status,
+				"notice": StatusNotice(app),
+			},
+		})
+		c.Abort()
+		return false
+	case models.AppStatusDeprecated:
+		// 已停用的应用继续提供服务，但通过响应头提示客户端
+		c.Header("Deprecation", "true")
+	}
+	return true
+}
+
+// RequireScope 检查当前API密钥是否拥有指定权限范围，需在 APIKeyMiddleware 之后使用
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scopes, exists := c.Get("apiKeyScopes")
+		if !exists || !scopes.(models.ScopeList).Contains(scope) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"code":    403,
+				"message": "API密钥缺少权限: " + scope,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// resolveAPIKey 根据密钥查找应用，返回密钥类型及其权限范围
+// 先按明文前缀查找候选记录，再以常量时间比较哈希
+func resolveAPIKey(apiKey string) (*models.Application, string, models.ScopeList, error) {
+	now := time.Now()
+	prefix := utils.APIKeyPrefix(apiKey)
+
+	// 应用主密钥拥有全部权限，轮换后的旧密钥在宽限期内同样有效
+	var apps []models.Application
+	if err := config.DB.Where("api_key_prefix = ? OR (previous_api_key_prefix = ? AND previous_api_key_expires_at > ?)",
+		prefix, prefix, now).Find(&apps).Error; err != nil {
+		return nil, "", nil, err
+	}
+	for i := range apps {
+		app := &apps[i]
+		if utils.VerifyAPIKey(apiKey, app.APIKeyHash) {
+			return app, APIKeyTypeCurrent, models.ScopeList(models.AllScopes), nil
+		}
+		if app.PreviousAPIKeyExpiresAt != nil && app.PreviousAPIKeyExpiresAt.After(now) &&
+			utils.VerifyAPIKey(apiKey, app.PreviousAPIKeyHash) {
+			return app, APIKeyTypePrevious, models.ScopeList(models.AllScopes), nil
+		}
+	}
+
+	// 命名密钥只拥有创建时指定的权限
+	var keys []models.APIKey
+	if err := config.DB.Where("prefix = ?", prefix).Find(&keys).Error; err != nil {
+		return nil, "", nil, err
+	}
+	for i := range keys {
+		key := &keys[i]
+		if !utils.VerifyAPIKey(apiKey, key.KeyHash) {
+			continue
+		}
+		if !key.IsActive(now) {
+			return nil, "", nil, errors.New("API密钥已失效")
+		}
+
+		var app models.Application
+		if err := config.DB.First(&app, key.AppID).Error; err != nil {
+			return nil, "", nil, err
+		}
+
+		// 记录最近使用时间，限制写入频率
+		if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > time.Minute {
+			config.DB.Model(key).UpdateColumn("last_used_at", now)
+		}
+
+		return &app, APIKeyTypeScoped, key.Scopes, nil
+	}
+
+	return nil, "", nil, errors.New("无效的API密钥")
+}
+
+// StatusNotice 返回应用当前状态对应的提示信息，正常状态返回空字符串
+func StatusNotice(app *models.Application) string {
+	switch app.Status {
+	case models.AppStatusMaintenance:
+		if app.StatusMessage != "" {
+			return app.StatusMessage
+		}
+		return defaultMaintenanceNotice
+	case models.AppStatusDeprecated:
+		if app.StatusMessage != "" {
+			return app.StatusMessage
+		}
+		return defaultSunsetMessage
+	}
+	return ""
+}
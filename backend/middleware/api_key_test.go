@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"app_management/config"
+	"app_management/models"
+	"app_management/services"
+	"app_management/utils"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// APIKeyMiddlewareTestSuite API密钥中间件测试套件
+type APIKeyMiddlewareTestSuite struct {
+	suite.Suite
+	router *gin.Engine
+}
+
+// SetupSuite 设置测试套件
+func (suite *APIKeyMiddlewareTestSuite) SetupSuite() {
+	config.SetupTestDB(suite.T())
+	config.SetupTestRedis(suite.T())
+
+	gin.SetMode(gin.TestMode)
+	suite.router = gin.New()
+	suite.router.GET("/version", APIKeyMiddleware(), RequireScope(models.ScopeVersionRead), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	suite.router.GET("/download/:artifactId", DownloadMiddleware(), RequireScope(models.ScopeVersionRead), func(c *gin.Context) {
+		c.String(http.StatusOK, "%d", c.MustGet("app").(*models.Application).ID)
+	})
+}
+
+// TearDownSuite 清理测试套件
+func (suite *APIKeyMiddlewareTestSuite) TearDownSuite() {
+	config.CleanupTestDB(suite.T())
+}
+
+// SetupTest 设置单个测试
+func (suite *APIKeyMiddlewareTestSuite) SetupTest() {
+	config.DB.Exec("DELETE FROM api_keys")
+	config.DB.Exec("DELETE FROM applications")
+}
+
+// request 使用指定密钥请求需要 version:read 权限的接口
+func (suite *APIKeyMiddlewareTestSuite) request(key string) int {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/version", nil)
+	req.Header.Set("X-API-Key", key)
+	suite.router.ServeHTTP(w, req)
+	return w.Code
+}
+
+// TestRequireScope 测试吊销、过期和缺少权限范围的密钥都被拒绝
+func (suite *APIKeyMiddlewareTestSuite) TestRequireScope() {
+	app, err := services.NewAppService().CreateApplication("中间件应用", "测试API密钥中间件")
+	assert.NoError(suite.T(), err)
+	apiKeyService := services.NewAPIKeyService()
+
+	future := time.Now().Add(time.Hour)
+	versionKey, err := apiKeyService.CreateAPIKey(app.ID, &models.CreateAPIKeyRequest{Name: "版本查询", Scopes: []string{models.ScopeVersionRead}})
+	assert.NoError(suite.T(), err)
+	expiringKey, err := apiKeyService.CreateAPIKey(app.ID, &models.CreateAPIKeyRequest{Name: "临时密钥", Scopes: []string{models.ScopeVersionRead}, ExpiresAt: &future})
+	assert.NoError(suite.T(), err)
+	memberKey, err := apiKeyService.CreateAPIKey(app.ID, &models.CreateAPIKeyRequest{Name: "会员查询", Scopes: []string{models.ScopeMemberLevelsRead}})
+	assert.NoError(suite.T(), err)
+
+	// 应用主密钥拥有全部权限范围
+	assert.Equal(suite.T(), http.StatusOK, suite.request(app.APIKey))
+	assert.Equal(suite.T(), http.StatusOK, suite.request(versionKey.Key))
+	assert.Equal(suite.T(), http.StatusOK, suite.request(expiringKey.Key))
+
+	// 缺少所需权限范围
+	assert.Equal(suite.T(), http.StatusForbidden, suite.request(memberKey.Key))
+
+	// 已吊销的密钥
+	_, err = apiKeyService.RevokeAPIKey(app.ID, versionKey.ID)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusUnauthorized, suite.request(versionKey.Key))
+
+	// 已过期的密钥
+	config.DB.Model(&models.APIKey{}).Where("id = ?", expiringKey.ID).Update("expires_at", time.Now().Add(-time.Minute))
+	assert.Equal(suite.T(), http.StatusUnauthorized, suite.request(expiringKey.Key))
+
+	// 无效密钥
+	assert.Equal(suite.T(), http.StatusUnauthorized, suite.request("invalid"))
+}
+
+// TestDownloadToken 测试构建产物下载接受请求头密钥或限时下载令牌
+func (suite *APIKeyMiddlewareTestSuite) TestDownloadToken() {
+	app, err := services.NewAppService().CreateApplication("下载应用", "测试下载令牌")
+	assert.NoError(suite.T(), err)
+
+	download := func(path, key string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		suite.router.ServeHTTP(w, req)
+		return w
+	}
+
+	// 请求头中的API密钥
+	assert.Equal(suite.T(), http.StatusOK, download("/download/42", app.APIKey).Code)
+
+	// 下载地址不再接受 apiKey 查询参数
+	assert.Equal(suite.T(), http.StatusUnauthorized, download("/download/42?apiKey="+app.APIKey, "").Code)
+
+	// 有效的下载令牌
+	secret := config.DownloadTokenSecret()
+	token := utils.SignDownloadToken(secret, app.ID, 42, time.Now().Add(time.Minute))
+	w := download("/download/42?token="+token, "")
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Equal(suite.T(), fmt.Sprint(app.ID), w.Body.String())
+
+	// 令牌不能用于其他构建产物，过期后失效
+	assert.Equal(suite.T(), http.StatusUnauthorized, download("/download/43?token="+token, "").Code)
+	expired := utils.SignDownloadToken(secret, app.ID, 42, time.Now().Add(-time.Minute))
+	assert.Equal(suite.T(), http.StatusUnauthorized, download("/download/42?token="+expired, "").Code)
+}
+
+// 运行测试套件
+func TestAPIKeyMiddlewareTestSuite(t *testing.T) {
+	suite.Run(t, new(APIKeyMiddlewareTestSuite))
+}
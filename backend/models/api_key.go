@@ -0,0 +1,105 @@
+package models
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"time"
+)
+
+// API密钥权限范围
+const (
+	ScopeVersionRead      = "version:read"
+	ScopeMemberLevelsRead = "member-levels:read"
+	ScopeMembersRead      = "members:read" // 查询终端用户的会员信息
+)
+
+// AllScopes 系统支持的全部权限范围，应用主密钥拥有全部权限
+var AllScopes = []string{
+	ScopeVersionRead,
+	ScopeMemberLevelsRead,
+	ScopeMembersRead,
+}
+
+// IsValidScope 检查权限范围是否受支持
+func IsValidScope(scope string) bool {
+	for _, s := range AllScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// ScopeList 权限范围列表，数据库中以逗号分隔存储
+type ScopeList []string
+
+// Contains 检查是否包含指定权限范围
+func (l ScopeList) Contains(scope string) bool {
+	for _, s := range l {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Value 实现 driver.Valuer 接口
+func (l ScopeList) Value() (driver.Value, error) {
+	return strings.Join(l, ","), nil
+}
+
+// Scan 实现 sql.Scanner 接口
+func (l *ScopeList) Scan(value interface{}) error {
+	var str string
+	switch v := value.(type) {
+	case []byte:
+		str = string(v)
+	case string:
+		str = v
+	case nil:
+		*l = ScopeList{}
+		return nil
+	default:
+		return errors.New("无法解析权限范围")
+	}
+
+	*l = ScopeList{}
+	for _, s := range strings.Split(str, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			*l = append(*l, s)
+		}
+	}
+	return nil
+}
+
+// APIKey 应用的命名API密钥，每个密钥拥有独立的权限范围
+type APIKey struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	AppID      uint       `json:"appId" gorm:"not null;index"`
+	Name       string     `json:"name" gorm:"size:50;not null"`
+	Key        string     `json:"key,omitempty" gorm:"-"` // 明文密钥仅在创建时返回一次
+	Prefix     string     `json:"prefix" gorm:"size:16;index"`
+	KeyHash    string     `json:"-" gorm:"size:64"`
+	Scopes     ScopeList  `json:"scopes" gorm:"size:255"`
+	ExpiresAt  *time.Time `json:"expiresAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	RevokedAt  *time.Time `json:"revokedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+}
+
+// IsActive 检查密钥当前是否可用
+func (k *APIKey) IsActive(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || k.ExpiresAt.After(now)
+}
+
+// CreateAPIKeyRequest 创建API密钥请求
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required"`
+	Scopes    []string   `json:"scopes" binding:"required"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
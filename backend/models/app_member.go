@@ -0,0 +1,85 @@
+package models
+
+import "time"
+
+// 会员订阅状态
+const (
+	AppMemberStatusActive    = "active"    // 正常，在有效期内享有会员等级
+	AppMemberStatusSuspended = "suspended" // 暂停，可以恢复
+	AppMemberStatusCancelled = "cancelled" // 已取消
+	AppMemberStatusExpired   = "expired"   // 已过期，仅用于展示，由有效期计算得出
+	AppMemberStatusPending   = "pending"   // 未到开始时间，仅用于展示，由有效期计算得出
+)
+
+// IsValidAppMemberStatus 检查可保存的会员订阅状态
+func IsValidAppMemberStatus(status string) bool {
+	switch status {
+	case AppMemberStatusActive, AppMemberStatusSuspended, AppMemberStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// AppMember 终端用户在应用中的会员订阅，每个用户在一个应用中只有一条记录
+type AppMember struct {
+	ID             uint        `json:"id" gorm:"primaryKey"`
+	AppID          uint        `json:"appId" gorm:"not null;uniqueIndex:idx_app_external_user"`
+	ExternalUserID string      `json:"externalUserId" gorm:"size:128;not null;uniqueIndex:idx_app_external_user"` // 应用自己的用户ID
+	MemberLevelID  uint        `json:"memberLevelId" gorm:"not null;index"`
+	StartsAt       time.Time   `json:"startsAt"`
+	ExpiresAt      *time.Time  `json:"expiresAt"` // 为空表示永久有效
+	Status         string      `json:"status" gorm:"size:20;default:'active'"`
+	CreatedAt      time.Time   `json:"createdAt"`
+	UpdatedAt      time.Time   `json:"updatedAt"`
+	MemberLevel    MemberLevel `json:"memberLevel" gorm:"foreignKey:MemberLevelID"`
+}
+
+// CurrentStatus 返回会员订阅当前的状态，已过期或未开始的订阅返回 expired 或 pending
+func (m *AppMember) CurrentStatus(now time.Time) string {
+	if m.Status != AppMemberStatusActive {
+		return m.Status
+	}
+	if now.Before(m.StartsAt) {
+		return AppMemberStatusPending
+	}
+	if m.ExpiresAt != nil && !m.ExpiresAt.After(now) {
+		return AppMemberStatusExpired
+	}
+	return AppMemberStatusActive
+}
+
+// IsCurrent 会员订阅当前是否生效
+func (m *AppMember) IsCurrent(now time.Time) bool {
+	return m.CurrentStatus(now) == AppMemberStatusActive
+}
+
+// CreateAppMemberRequest 创建会员订阅请求
+type CreateAppMemberRequest struct {
+	ExternalUserID string `json:"externalUserId" binding:"required"`
+	MemberLevelID  uint   `json:"memberLevelId" binding:"required"`
+	// 开始时间，为空时立即生效
+	StartsAt  *time.Time `json:"startsAt"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+	// 为空时为 active
+	Status string `json:"status"`
+}
+
+// UpdateAppMemberRequest 更新会员订阅请求，未提供的字段保持不变
+type UpdateAppMemberRequest struct {
+	MemberLevelID *uint      `json:"memberLevelId"`
+	StartsAt      *time.Time `json:"startsAt"`
+	ExpiresAt     *time.Time `json:"expiresAt"`
+	// 为 true 时取消到期时间，改为永久有效
+	NeverExpires bool    `json:"neverExpires"`
+	Status       *string `json:"status"`
+}
+
+// Membership 终端用户当前的会员信息
+type Membership struct {
+	ExternalUserID string                `json:"externalUserId"`
+	Status         string                `json:"status"`
+	Active         bool                  `json:"active"`
+	StartsAt       time.Time             `json:"startsAt"`
+	ExpiresAt      *time.Time            `json:"expiresAt"`
+	Level          *EffectiveMemberLevel `json:"level"` // 订阅未生效时为空
+}
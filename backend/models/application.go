@@ -6,30 +6,194 @@ import (
 	"gorm.io/gorm"
 )
 
+// 应用状态
+const (
+	AppStatusActive      = "active"
+	AppStatusMaintenance = "maintenance"
+	AppStatusDeprecated  = "deprecated"
+)
+
+// 版本发布渠道，按稳定性从高到低排列
+const (
+	ChannelStable  = "stable"
+	ChannelBeta    = "beta"
+	ChannelNightly = "nightly"
+)
+
+// Channels 全部发布渠道，按稳定性从高到低排列
+var Channels = []string{ChannelStable, ChannelBeta, ChannelNightly}
+
+// ChannelRank 返回渠道的稳定性排序，数值越小越稳定，未知渠道返回-1
+func ChannelRank(channel string) int {
+	for i, c := range Channels {
+		if c == channel {
+			return i
+		}
+	}
+	return -1
+}
+
+// VisibleChannels 返回订阅某渠道的客户端可见的渠道，如 beta 用户同时可见 stable 版本
+func VisibleChannels(channel string) []string {
+	rank := ChannelRank(channel)
+	if rank < 0 {
+		return nil
+	}
+	return Channels[:rank+1]
+}
+
+// 灰度发布状态
+const (
+	RolloutStatusActive = "active" // 按比例向客户端推送
+	RolloutStatusPaused = "paused" // 暂停推送，可以恢复
+	RolloutStatusHalted = "halted" // 终止推送，不可恢复
+)
+
+// 版本发布状态
+const (
+	PublishStatusDraft     = "draft"     // 草稿，不对客户端可见
+	PublishStatusScheduled = "scheduled" // 等待定时发布
+	PublishStatusPublished = "published" // 已发布
+)
+
 // Application 应用模型
 type Application struct {
-	ID            uint           `json:"id" gorm:"primaryKey"`
-	Name          string         `json:"name" gorm:"size:20;not null;uniqueIndex"`
-	Description   string         `json:"description" gorm:"size:200"`
-	LatestVersion string         `json:"latestVersion" gorm:"size:20"`
-	Status        string         `json:"status" gorm:"size:20;default:'active'"`
-	APIKey        string         `json:"apiKey" gorm:"size:64;uniqueIndex;not null"`
-	CreatedAt     time.Time      `json:"createdAt"`
-	UpdatedAt     time.Time      `json:"updatedAt"`
-	DeletedAt     gorm.DeletedAt `json:"deletedAt" gorm:"index"`
-	Versions      []Version      `json:"versions" gorm:"foreignKey:AppID"`
-	MemberLevels  []MemberLevel  `json:"memberLevels" gorm:"foreignKey:AppID"`
+	ID                      uint           `json:"id" gorm:"primaryKey"`
+	Name                    string         `json:"name" gorm:"size:20;not null;uniqueIndex"`
+	Description             string         `json:"description" gorm:"size:200"`
+	LatestVersion           string         `json:"latestVersion" gorm:"size:64"`
+	MinSupportedVersion     string         `json:"minSupportedVersion" gorm:"size:64"` // 低于此版本的客户端必须更新
+	Status                  string         `json:"status" gorm:"size:20;default:'active'"`
+	StatusMessage           string         `json:"statusMessage" gorm:"size:200"` // 维护公告或停用说明
+	APIKey                  string         `json:"apiKey,omitempty" gorm:"-"`     // 明文密钥仅在创建或轮换时返回一次
+	APIKeyPrefix            string         `json:"apiKeyPrefix" gorm:"size:16;index"`
+	APIKeyHash              string         `json:"-" gorm:"size:64"`
+	PreviousAPIKeyPrefix    string         `json:"-" gorm:"size:16;index"` // 轮换前的旧密钥，宽限期内仍然有效
+	PreviousAPIKeyHash      string         `json:"-" gorm:"size:64"`
+	PreviousAPIKeyExpiresAt *time.Time     `json:"previousApiKeyExpiresAt"`
+	PublicFeedEnabled       bool           `json:"publicFeedEnabled" gorm:"not null;default:false"`
+	CreatedAt               time.Time      `json:"createdAt"`
+	UpdatedAt               time.Time      `json:"updatedAt"`
+	DeletedAt               gorm.DeletedAt `json:"deletedAt" gorm:"index"`
+	Versions                []Version      `json:"versions" gorm:"foreignKey:AppID"`
+	MemberLevels            []MemberLevel  `json:"memberLevels" gorm:"foreignKey:AppID"`
+}
+
+// UpdateApplicationRequest 更新应用请求，未提供的字段保持不变
+type UpdateApplicationRequest struct {
+	Name          *string `json:"name"`
+	Description   *string `json:"description"`
+	Status        *string `json:"status"`
+	StatusMessage *string `json:"statusMessage"`
+	// 最低支持版本，空字符串表示取消限制
+	MinSupportedVersion *string `json:"minSupportedVersion"`
+	PublicFeedEnabled   *bool   `json:"publicFeedEnabled"`
+}
+
+// RotateAPIKeyRequest 轮换API密钥请求
+type RotateAPIKeyRequest struct {
+	// 旧密钥的宽限期（小时），为空时使用系统默认值，为0时旧密钥立即失效
+	GracePeriodHours *int `json:"gracePeriodHours"`
 }
 
 // Version 版本模型
 type Version struct {
-	ID            uint           `json:"id" gorm:"primaryKey"`
-	AppID         uint           `json:"appId" gorm:"not null"`
-	Version       string         `json:"version" gorm:"size:20;not null"`
-	ChangelogMD   string         `json:"changelogMd" gorm:"type:text"`
-	ChangelogHTML string         `json:"changelogHtml" gorm:"type:text"`
-	CreatedAt     time.Time      `json:"createdAt"`
-	UpdatedAt     time.Time      `json:"updatedAt"`
-	DeletedAt     gorm.DeletedAt `json:"deletedAt" gorm:"index"`
-	Application   Application    `json:"application" gorm:"foreignKey:AppID"`
-} 
\ No newline at end of file
+	ID                uint           `json:"id" gorm:"primaryKey"`
+	AppID             uint           `json:"appId" gorm:"not null"`
+	Version           string         `json:"version" gorm:"size:64;not null"`
+	Channel           string         `json:"channel" gorm:"size:20;default:'stable';index"`
+	ForceUpdate       bool           `json:"forceUpdate" gorm:"default:false"` // 跳过此版本的客户端必须更新
+	Breaking          bool           `json:"breaking" gorm:"default:false"`    // 包含不兼容变更
+	Security          bool           `json:"security" gorm:"default:false"`    // 包含安全修复
+	RolloutPercentage int            `json:"rolloutPercentage" gorm:"not null;default:100"`
+	RolloutStatus     string         `json:"rolloutStatus" gorm:"size:20;default:'active'"`
+	Yanked            bool           `json:"yanked" gorm:"default:false"` // 已撤回的版本不再提供给客户端，但保留记录
+	YankedAt          *time.Time     `json:"yankedAt"`
+	YankReason        string         `json:"yankReason" gorm:"size:200"`
+	PublishStatus     string         `json:"publishStatus" gorm:"size:20;default:'published';index"`
+	PublishAt         *time.Time     `json:"publishAt"` // 定时发布时间
+	PublishedAt       *time.Time     `json:"publishedAt"`
+	ChangelogMD       string         `json:"changelogMd" gorm:"type:text"`
+	ChangelogHTML     string         `json:"changelogHtml" gorm:"type:text"`
+	CreatedAt         time.Time      `json:"createdAt"`
+	UpdatedAt         time.Time      `json:"updatedAt"`
+	DeletedAt         gorm.DeletedAt `json:"deletedAt" gorm:"index"`
+	Application       Application    `json:"application" gorm:"foreignKey:AppID"`
+	// Entries 结构化更新日志条目，存在时 ChangelogMD 由条目生成
+	Entries []ChangelogEntry `json:"entries,omitempty" gorm:"foreignKey:VersionID"`
+	// Changelogs 其他语言的更新日志
+	Changelogs []LocalizedChangelog `json:"changelogs,omitempty" gorm:"foreignKey:VersionID"`
+}
+
+// CreateVersionRequest 创建版本请求
+type CreateVersionRequest struct {
+	Version     string `json:"version" binding:"required"`
+	ChangelogMD string `json:"changelogMd"`
+	Channel     string `json:"channel"` // 为空时发布到 stable 渠道
+	ForceUpdate bool   `json:"forceUpdate"`
+	Breaking    bool   `json:"breaking"`
+	Security    bool   `json:"security"`
+	// 结构化更新日志条目，提供时根据条目生成 changelogMd
+	Entries []ChangelogEntryRequest `json:"entries"`
+	// 多语言更新日志，键为 BCP-47 语言标签，值为Markdown
+	Changelogs map[string]string `json:"changelogs"`
+	// 灰度发布比例（0-100），为空时全量发布
+	RolloutPercentage *int `json:"rolloutPercentage"`
+	// 发布状态：draft、scheduled、published，为空时若指定了 publishAt 则定时发布，否则立即发布
+	PublishStatus string     `json:"publishStatus"`
+	PublishAt     *time.Time `json:"publishAt"`
+	// 允许发布不高于当前最新版本的版本号，如为旧版本补发修复
+	AllowRegression bool `json:"allowRegression"`
+}
+
+// UpdateVersionRequest 更新版本请求，未提供的字段保持不变
+type UpdateVersionRequest struct {
+	// 不带 Entries 时替换为自由格式的更新日志，并清除原有的结构化条目
+	ChangelogMD *string `json:"changelogMd"`
+	ForceUpdate *bool   `json:"forceUpdate"`
+	Breaking    *bool   `json:"breaking"`
+	Security    *bool   `json:"security"`
+	// 结构化更新日志条目，提供时整体替换原有条目，空数组表示清除条目
+	Entries *[]ChangelogEntryRequest `json:"entries"`
+	// 多语言更新日志，提供时整体替换原有内容，空对象表示清除全部语言
+	Changelogs map[string]string `json:"changelogs"`
+}
+
+// YankVersionRequest 撤回版本请求
+type YankVersionRequest struct {
+	Reason string `json:"reason"`
+}
+
+// ScheduleVersionRequest 定时发布版本请求
+type ScheduleVersionRequest struct {
+	PublishAt time.Time `json:"publishAt" binding:"required"`
+}
+
+// IsPublished 版本是否已发布
+func (v *Version) IsPublished() bool {
+	return v.PublishStatus == PublishStatusPublished
+}
+
+// ReleasedAt 版本对外的发布时间，历史版本没有发布时间时使用创建时间
+func (v *Version) ReleasedAt() time.Time {
+	if v.PublishedAt != nil {
+		return *v.PublishedAt
+	}
+	return v.CreatedAt
+}
+
+// IsFullyRolledOut 版本是否已全量发布
+func (v *Version) IsFullyRolledOut() bool {
+	return v.RolloutStatus == RolloutStatusActive && v.RolloutPercentage >= 100
+}
+
+// UpdateRolloutRequest 调整灰度发布请求，未提供的字段保持不变
+type UpdateRolloutRequest struct {
+	Percentage *int    `json:"percentage"`
+	Status     *string `json:"status"`
+}
+
+// PromoteVersionRequest 提升版本发布渠道请求
+type PromoteVersionRequest struct {
+	Channel string `json:"channel" binding:"required"`
+}
@@ -0,0 +1,131 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 构建产物支持的平台
+var ArtifactPlatforms = []string{"windows", "macos", "linux", "android", "ios"}
+
+// 构建产物支持的架构，移动平台可以不指定架构
+var ArtifactArchs = []string{"x64", "x86", "arm64", "universal"}
+
+// VersionArtifact 版本的构建产物，每个平台和架构一个下载文件
+type VersionArtifact struct {
+	ID        uint   `json:"id" gorm:"primaryKey"`
+	AppID     uint   `json:"appId" gorm:"not null;index"`
+	VersionID uint   `json:"versionId" gorm:"not null;uniqueIndex:idx_version_artifacts_target"`
+	Platform  string `json:"platform" gorm:"size:20;not null;uniqueIndex:idx_version_artifacts_target"`
+	Arch      string `json:"arch" gorm:"size:20;uniqueIndex:idx_version_artifacts_target"`
+	FileName  string `json:"fileName" gorm:"size:255"`
+	URL       string `json:"url" gorm:"size:500;not null"`
+	Size      int64  `json:"size"`
+	SHA256    string `json:"sha256" gorm:"size:64"`
+	SHA1      string `json:"sha1,omitempty" gorm:"size:40"` // Squirrel.Windows 的 RELEASES 文件需要
+
+	EdSignature          string `json:"edSignature,omitempty" gorm:"size:128"`         // 文件的 Ed25519 签名（Base64），供 Sparkle 校验
+	MinimumSystemVersion string `json:"minimumSystemVersion,omitempty" gorm:"size:32"` // 最低系统版本，如 macOS 的 10.15
+	UpdaterSignature     string `json:"updaterSignature,omitempty" gorm:"type:text"`   // 更新框架要求的签名文件内容，如 Tauri 的 .sig
+
+	StorageKey string    `json:"-" gorm:"size:500"` // 托管在本服务存储中的对象键，外部地址的产物为空
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// Target 返回产物的目标平台标识，如 windows-x64、android
+func (a *VersionArtifact) Target() string {
+	if a.Arch == "" {
+		return a.Platform
+	}
+	return a.Platform + "-" + a.Arch
+}
+
+// IsHosted 产物文件是否托管在本服务的存储中
+func (a *VersionArtifact) IsHosted() bool {
+	return a.StorageKey != ""
+}
+
+// ArtifactDownloadPath 返回托管产物的下载路径
+func ArtifactDownloadPath(artifactID uint) string {
+	return fmt.Sprintf("/api/v1/external/download/%d", artifactID)
+}
+
+// MatchArtifact 按架构匹配构建产物：优先精确匹配，其次通用包，最后是不区分架构的产物
+// 客户端未指定架构且只有一个产物时直接返回该产物
+func MatchArtifact(artifacts []VersionArtifact, arch string) *VersionArtifact {
+	for _, preferred := range []string{arch, "universal", ""} {
+		for i := range artifacts {
+			if artifacts[i].Arch == preferred {
+				return &artifacts[i]
+			}
+		}
+	}
+	if arch == "" && len(artifacts) == 1 {
+		return &artifacts[0]
+	}
+	return nil
+}
+
+// ParseArtifactTarget 解析平台标识，支持 windows-x64 形式，arch 参数优先
+func ParseArtifactTarget(platform, arch string) (string, string) {
+	platform = strings.ToLower(strings.TrimSpace(platform))
+	arch = strings.ToLower(strings.TrimSpace(arch))
+	if i := strings.IndexByte(platform, '-'); i >= 0 {
+		if arch == "" {
+			arch = platform[i+1:]
+		}
+		platform = platform[:i]
+	}
+	return platform, arch
+}
+
+// IsValidArtifactPlatform 检查平台是否受支持
+func IsValidArtifactPlatform(platform string) bool {
+	for _, p := range ArtifactPlatforms {
+		if p == platform {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidArtifactArch 检查架构是否受支持，空字符串表示不区分架构
+func IsValidArtifactArch(arch string) bool {
+	if arch == "" {
+		return true
+	}
+	for _, a := range ArtifactArchs {
+		if a == arch {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateArtifactRequest 添加构建产物请求
+type CreateArtifactRequest struct {
+	Platform string `json:"platform" binding:"required"` // 如 windows、macos，也可以是 windows-x64
+	Arch     string `json:"arch"`
+	FileName string `json:"fileName"`
+	URL      string `json:"url" binding:"required"`
+	Size     int64  `json:"size"`
+	SHA256   string `json:"sha256"`
+	SHA1     string `json:"sha1"`
+	// EdSignature 文件的 Ed25519 签名（Base64），如 Sparkle sign_update 的输出
+	EdSignature          string `json:"edSignature"`
+	MinimumSystemVersion string `json:"minimumSystemVersion"`
+	UpdaterSignature     string `json:"updaterSignature"`
+}
+
+// UploadArtifactRequest 上传构建产物请求，字段来自 multipart 表单
+type UploadArtifactRequest struct {
+	Platform             string
+	Arch                 string
+	FileName             string
+	SHA256               string // 可选，提供时与上传内容的校验值比对
+	EdSignature          string
+	MinimumSystemVersion string
+	UpdaterSignature     string
+}
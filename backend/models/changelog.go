@@ -0,0 +1,153 @@
+package models
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 更新日志条目分类，参考 Keep a Changelog
+const (
+	ChangelogAdded      = "added"
+	ChangelogChanged    = "changed"
+	ChangelogDeprecated = "deprecated"
+	ChangelogRemoved    = "removed"
+	ChangelogFixed      = "fixed"
+	ChangelogSecurity   = "security"
+)
+
+// ChangelogCategories 全部条目分类，同时也是生成Markdown时的分组顺序
+var ChangelogCategories = []string{
+	ChangelogAdded,
+	ChangelogChanged,
+	ChangelogDeprecated,
+	ChangelogRemoved,
+	ChangelogFixed,
+	ChangelogSecurity,
+}
+
+// changelogCategoryTitles 生成Markdown时各分类的标题
+var changelogCategoryTitles = map[string]string{
+	ChangelogAdded:      "Added",
+	ChangelogChanged:    "Changed",
+	ChangelogDeprecated: "Deprecated",
+	ChangelogRemoved:    "Removed",
+	ChangelogFixed:      "Fixed",
+	ChangelogSecurity:   "Security",
+}
+
+// IsValidChangelogCategory 检查条目分类是否受支持
+func IsValidChangelogCategory(category string) bool {
+	_, ok := changelogCategoryTitles[category]
+	return ok
+}
+
+// IssueRefList 关联的问题编号或链接，数据库中以逗号分隔存储
+type IssueRefList []string
+
+// Value 实现 driver.Valuer 接口
+func (l IssueRefList) Value() (driver.Value, error) {
+	return strings.Join(l, ","), nil
+}
+
+// Scan 实现 sql.Scanner 接口
+func (l *IssueRefList) Scan(value interface{}) error {
+	var str string
+	switch v := value.(type) {
+	case []byte:
+		str = string(v)
+	case string:
+		str = v
+	case nil:
+		*l = IssueRefList{}
+		return nil
+	default:
+		return errors.New("无法解析关联问题")
+	}
+
+	*l = IssueRefList{}
+	for _, s := range strings.Split(str, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			*l = append(*l, s)
+		}
+	}
+	return nil
+}
+
+// ChangelogEntry 结构化更新日志条目
+type ChangelogEntry struct {
+	ID          uint         `json:"id" gorm:"primaryKey"`
+	VersionID   uint         `json:"versionId" gorm:"not null;index"`
+	Category    string       `json:"category" gorm:"size:20;not null"`
+	Description string       `json:"description" gorm:"type:text;not null"`
+	Issues      IssueRefList `json:"issues" gorm:"size:500"`
+	Position    int          `json:"position"` // 条目在版本中的顺序
+	CreatedAt   time.Time    `json:"createdAt"`
+}
+
+// ChangelogEntryRequest 更新日志条目请求
+type ChangelogEntryRequest struct {
+	Category    string   `json:"category"`
+	Description string   `json:"description"`
+	Issues      []string `json:"issues"` // 如 "123"、"#123" 或问题链接
+}
+
+// LocalizedChangelog 版本的多语言更新日志，Version.ChangelogMD 作为默认语言的内容
+type LocalizedChangelog struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	VersionID     uint      `json:"versionId" gorm:"not null;uniqueIndex:idx_version_locale"`
+	Locale        string    `json:"locale" gorm:"size:35;not null;uniqueIndex:idx_version_locale"` // BCP-47 语言标签，如 zh-CN、en
+	ChangelogMD   string    `json:"changelogMd" gorm:"type:text"`
+	ChangelogHTML string    `json:"changelogHtml" gorm:"type:text"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// ChangelogMarkdown 按分类分组生成 Keep a Changelog 风格的Markdown
+func ChangelogMarkdown(entries []ChangelogEntry) string {
+	var sections []string
+	for _, category := range ChangelogCategories {
+		var lines []string
+		for _, entry := range entries {
+			if entry.Category != category {
+				continue
+			}
+			line := "- " + strings.TrimSpace(entry.Description)
+			if len(entry.Issues) > 0 {
+				refs := make([]string, len(entry.Issues))
+				for i, issue := range entry.Issues {
+					refs[i] = formatIssueRef(issue)
+				}
+				line += fmt.Sprintf(" (%s)", strings.Join(refs, ", "))
+			}
+			lines = append(lines, line)
+		}
+		if len(lines) > 0 {
+			sections = append(sections, "### "+changelogCategoryTitles[category]+"\n\n"+strings.Join(lines, "\n"))
+		}
+	}
+	return strings.Join(sections, "\n\n")
+}
+
+// FilterChangelogEntries 返回指定分类的条目
+func FilterChangelogEntries(entries []ChangelogEntry, category string) []ChangelogEntry {
+	filtered := []ChangelogEntry{}
+	for _, entry := range entries {
+		if entry.Category == category {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+// formatIssueRef 纯数字的问题编号显示为 #123，其他内容原样显示
+func formatIssueRef(issue string) string {
+	for _, c := range issue {
+		if c < '0' || c > '9' {
+			return issue
+		}
+	}
+	return "#" + issue
+}
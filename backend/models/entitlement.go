@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// EntitlementCheckRequest 权益检查请求
+type EntitlementCheckRequest struct {
+	ExternalUserID string `json:"externalUserId" binding:"required"`
+	// 功能键（如 advanced）或JSON路径（如 limits.api_calls、/limits/api_calls）
+	Features []string `json:"features" binding:"required"`
+}
+
+// EntitlementResult 单项权益的检查结果
+type EntitlementResult struct {
+	Feature string      `json:"feature"`
+	Allowed bool        `json:"allowed"`
+	Value   interface{} `json:"value"` // 权限配置中对应的值，如额度上限
+}
+
+// EntitlementCheck 权益检查结果
+type EntitlementCheck struct {
+	ExternalUserID string              `json:"externalUserId"`
+	Active         bool                `json:"active"` // 用户是否有生效的会员订阅
+	Level          *MemberLevelSummary `json:"level"`
+	Results        []EntitlementResult `json:"results"`
+	CheckedAt      time.Time           `json:"checkedAt"`
+}
+
+// MemberLevelSummary 会员等级概要
+type MemberLevelSummary struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Level int    `json:"level"`
+}
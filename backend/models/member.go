@@ -1,6 +1,9 @@
 package models
 
 import (
+	"bytes"
+	"encoding/json"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -13,12 +16,57 @@ type MemberLevel struct {
 	Name        string         `json:"name" gorm:"size:20;not null"`
 	Level       int            `json:"level" gorm:"not null"`
 	Permissions string         `json:"permissions" gorm:"type:json"`
+	ParentLevel *int           `json:"parentLevel"`                              // 显式指定继承的等级，为空时继承相邻的较低等级
+	Standalone  bool           `json:"standalone" gorm:"not null;default:false"` // 不继承任何等级
 	CreatedAt   time.Time      `json:"createdAt"`
 	UpdatedAt   time.Time      `json:"updatedAt"`
 	DeletedAt   gorm.DeletedAt `json:"deletedAt" gorm:"index"`
 	Application Application    `json:"application" gorm:"foreignKey:AppID"`
 }
 
+// MemberLevelRequest 会员等级请求
+type MemberLevelRequest struct {
+	Name  string `json:"name"`
+	Level int    `json:"level"`
+	// 权限配置，可以是JSON对象或JSON字符串
+	Permissions json.RawMessage `json:"permissions"`
+	// 继承的等级，为空时继承相邻的较低等级
+	ParentLevel *int `json:"parentLevel"`
+	// 为 true 时不继承任何等级
+	Standalone bool `json:"standalone"`
+}
+
+// PermissionsDocument 返回权限配置JSON文本，未配置时返回空字符串
+func (r *MemberLevelRequest) PermissionsDocument() (string, error) {
+	raw := bytes.TrimSpace(r.Permissions)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return "", nil
+	}
+	if raw[0] != '"' {
+		return string(raw), nil
+	}
+
+	var document string
+	if err := json.Unmarshal(raw, &document); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(document), nil
+}
+
+// EffectiveMemberLevel 合并继承后的会员等级权限
+type EffectiveMemberLevel struct {
+	ID          uint            `json:"id"`
+	Name        string          `json:"name"`
+	Level       int             `json:"level"`
+	Inherits    []int           `json:"inherits"` // 继承链，从直接父等级到最底层的等级
+	Permissions json.RawMessage `json:"permissions"`
+}
+
+// UpdateMemberLevelsRequest 更新会员等级请求，整体替换应用的全部会员等级
+type UpdateMemberLevelsRequest struct {
+	Levels []MemberLevelRequest `json:"levels" binding:"required"`
+}
+
 // AuditLog 审计日志模型
 type AuditLog struct {
 	ID         uint           `json:"id" gorm:"primaryKey"`
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// PermissionSchema 应用会员等级权限配置的 JSON Schema，每个应用最多一个
+type PermissionSchema struct {
+	ID        uint            `json:"id" gorm:"primaryKey"`
+	AppID     uint            `json:"appId" gorm:"not null;uniqueIndex"`
+	Schema    json.RawMessage `json:"schema" gorm:"type:text;not null"`
+	CreatedAt time.Time       `json:"createdAt"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
+
+// UpdatePermissionSchemaRequest 注册权限配置 Schema 请求
+type UpdatePermissionSchemaRequest struct {
+	Schema json.RawMessage `json:"schema" binding:"required"`
+}
+
+// FieldError 指向具体字段的校验错误
+type FieldError struct {
+	Field   string `json:"field"` // 如 levels[1].permissions.limits.api_calls
+	Message string `json:"message"`
+}
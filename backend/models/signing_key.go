@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// 签名密钥状态
+const (
+	SigningKeyStatusActive  = "active"
+	SigningKeyStatusRetired = "retired"
+)
+
+// SignatureAlgorithmEd25519 清单签名算法
+const SignatureAlgorithmEd25519 = "Ed25519"
+
+// SigningKey 应用的更新清单签名密钥，私钥只保存在服务端
+type SigningKey struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	AppID      uint       `json:"appId" gorm:"not null;index"`
+	KeyID      string     `json:"keyId" gorm:"size:32;not null;uniqueIndex"`
+	Algorithm  string     `json:"algorithm" gorm:"size:20;not null"`
+	PublicKey  string     `json:"publicKey" gorm:"size:64;not null"` // Base64 编码的公钥
+	PrivateKey string     `json:"-" gorm:"size:128;not null"`        // 加密后的私钥种子
+	Status     string     `json:"status" gorm:"size:20;not null;default:'active'"`
+	ExpiresAt  *time.Time `json:"expiresAt"` // 轮换后旧公钥继续发布的截止时间
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+}
+
+// IsPublished 公钥是否仍对外发布：当前密钥或宽限期内的旧密钥
+func (k *SigningKey) IsPublished(now time.Time) bool {
+	if k.Status == SigningKeyStatusActive {
+		return true
+	}
+	return k.ExpiresAt != nil && now.Before(*k.ExpiresAt)
+}
+
+// ManifestSignature 响应数据的分离式签名，签名内容为 data 字段的规范化JSON
+type ManifestSignature struct {
+	KeyID     string `json:"keyId"`
+	Algorithm string `json:"algorithm"`
+	Signature string `json:"signature"` // Base64 编码
+}
+
+// RotateSigningKeyRequest 轮换签名密钥请求
+type RotateSigningKeyRequest struct {
+	GracePeriodHours *int `json:"gracePeriodHours"` // 旧公钥继续发布的小时数，不传则使用默认值
+}
@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// UpdateCheckRequest 客户端更新检查请求
+type UpdateCheckRequest struct {
+	Current  string `form:"current" binding:"required"`
+	Platform string `form:"platform"` // 如 android、windows-x64
+	Arch     string `form:"arch"`
+	Channel  string `form:"channel"`
+	ClientID string `form:"clientId"` // 设备或用户ID，用于灰度分桶
+	Format   string `form:"format"`   // 更新日志格式：markdown、html、text，默认 html
+	Category string `form:"category"` // 只返回指定分类的更新日志条目，如 security
+}
+
+// UpdateVersionInfo 更新检查结果中的单个版本
+type UpdateVersionInfo struct {
+	Version     string    `json:"version"`
+	Channel     string    `json:"channel"`
+	ForceUpdate bool      `json:"forceUpdate"`
+	Breaking    bool      `json:"breaking"`
+	Security    bool      `json:"security"`
+	Changelog   string    `json:"changelog"`
+	ReleasedAt  time.Time `json:"releasedAt"`
+}
+
+// UpdateCheckResult 客户端更新检查结果
+type UpdateCheckResult struct {
+	UpdateAvailable     bool                `json:"updateAvailable"`
+	Mandatory           bool                `json:"mandatory"`
+	CurrentVersion      string              `json:"currentVersion"`
+	LatestVersion       string              `json:"latestVersion,omitempty"`
+	MinSupportedVersion string              `json:"minSupportedVersion,omitempty"`
+	Channel             string              `json:"channel"`
+	Platform            string              `json:"platform,omitempty"`
+	Changelog           string              `json:"changelog"` // 当前版本之后所有版本的合并更新日志，新版本在前
+	ChangelogFormat     string              `json:"changelogFormat"`
+	Versions            []UpdateVersionInfo `json:"versions"`
+	Artifact            *VersionArtifact    `json:"artifact,omitempty"` // 最新版本中与客户端平台匹配的构建产物
+}
+
+// CompareVersionsRequest 版本对比请求，返回 from（不含）到 to（含）之间的版本
+type CompareVersionsRequest struct {
+	From    string `form:"from" binding:"required"`
+	To      string `form:"to" binding:"required"`
+	Channel string `form:"channel"` // 默认为 stable
+}
+
+// ComparedVersion 版本对比结果中的单个版本
+type ComparedVersion struct {
+	Version       string    `json:"version"`
+	Channel       string    `json:"channel"`
+	ForceUpdate   bool      `json:"forceUpdate"`
+	Breaking      bool      `json:"breaking"`
+	Security      bool      `json:"security"`
+	ChangelogMD   string    `json:"changelogMd"`
+	ChangelogHTML string    `json:"changelogHtml"`
+	ReleasedAt    time.Time `json:"releasedAt"`
+}
+
+// VersionComparison 版本对比结果
+type VersionComparison struct {
+	From             string            `json:"from"`
+	To               string            `json:"to"`
+	Channel          string            `json:"channel"`
+	Versions         []ComparedVersion `json:"versions"`    // 按版本从旧到新排列
+	ChangelogMD      string            `json:"changelogMd"` // 合并后的更新日志，旧版本在前
+	ChangelogHTML    string            `json:"changelogHtml"`
+	Breaking         bool              `json:"breaking"` // 区间内是否包含不兼容变更
+	Security         bool              `json:"security"` // 区间内是否包含安全更新
+	BreakingVersions []string          `json:"breakingVersions"`
+	SecurityVersions []string          `json:"securityVersions"`
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"app_management/config"
+	"app_management/models"
+	"app_management/utils"
+	"errors"
+	"time"
+)
+
+// APIKeyService 命名API密钥服务
+type APIKeyService struct{}
+
+// NewAPIKeyService 创建API密钥服务实例
+func NewAPIKeyService() *APIKeyService {
+	return &APIKeyService{}
+}
+
+// GetAPIKeys 获取应用的API密钥列表
+func (s *APIKeyService) GetAPIKeys(appID uint) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	result := config.DB.Where("app_id = ?", appID).Order("created_at DESC").Find(&keys)
+	return keys, result.Error
+}
+
+// CreateAPIKey 为应用创建一个命名API密钥
+func (s *APIKeyService) CreateAPIKey(appID uint, req *models.CreateAPIKeyRequest) (*models.APIKey, error) {
+	if len(req.Name) < 1 || len(req.Name) > 50 {
+		return nil, errors.New("密钥名称长度必须在1-50个字符之间")
+	}
+
+	if len(req.Scopes) == 0 {
+		return nil, errors.New("至少需要指定一个权限范围")
+	}
+	scopes := models.ScopeList{}
+	for _, scope := range req.Scopes {
+		if !models.IsValidScope(scope) {
+			return nil, errors.New("不支持的权限范围: " + scope)
+		}
+		if !scopes.Contains(scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, errors.New("过期时间必须晚于当前时间")
+	}
+
+	// 检查应用是否存在
+	var app models.Application
+	if err := config.DB.First(&app, appID).Error; err != nil {
+		return nil, errors.New("应用不存在")
+	}
+
+	key, err := utils.GenerateAPIKey()
+	if err != nil {
+		return nil, errors.New("生成API密钥失败")
+	}
+
+	// 数据库只保存密钥哈希，明文仅在本次响应中返回
+	apiKey := &models.APIKey{
+		AppID:     appID,
+		Name:      req.Name,
+		Prefix:    utils.APIKeyPrefix(key),
+		KeyHash:   utils.HashAPIKey(key),
+		Scopes:    scopes,
+		ExpiresAt: req.ExpiresAt,
+	}
+
+	if err := config.DB.Create(apiKey).Error; err != nil {
+		return nil, err
+	}
+	apiKey.Key = key
+
+	return apiKey, nil
+}
+
+// RevokeAPIKey 吊销应用的指定API密钥，不影响其他密钥
+func (s *APIKeyService) RevokeAPIKey(appID, keyID uint) (*models.APIKey, error) {
+	var apiKey models.APIKey
+	if err := config.DB.Where("id = ? AND app_id = ?", keyID, appID).First(&apiKey).Error; err != nil {
+		return nil, errors.New("API密钥不存在")
+	}
+
+	if apiKey.RevokedAt != nil {
+		return &apiKey, nil
+	}
+
+	now := time.Now()
+	apiKey.RevokedAt = &now
+	if err := config.DB.Model(&apiKey).Update("revoked_at", now).Error; err != nil {
+		return nil, err
+	}
+
+	return &apiKey, nil
+}
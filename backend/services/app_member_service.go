@@ -0,0 +1,248 @@
+package services
+
+import (
+	"app_management/config"
+	"app_management/models"
+	"app_management/utils"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrMemberNotFound 终端用户没有会员订阅
+var ErrMemberNotFound = errors.New("该用户没有会员订阅")
+
+// AppMemberService 终端用户会员订阅服务
+type AppMemberService struct {
+	memberService *MemberService
+}
+
+// NewAppMemberService 创建会员订阅服务实例
+func NewAppMemberService() *AppMemberService {
+	return &AppMemberService{
+		memberService: NewMemberService(),
+	}
+}
+
+// GetMembers 分页获取应用的会员订阅，可按状态过滤，返回记录和总数
+func (s *AppMemberService) GetMembers(appID uint, status string, limit, offset int) ([]models.AppMember, int64, error) {
+	query := config.DB.Model(&models.AppMember{}).Where("app_id = ?", appID)
+	if status != "" {
+		if !models.IsValidAppMemberStatus(status) {
+			return nil, 0, errors.New("无效的会员状态")
+		}
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var members []models.AppMember
+	result := query.Preload("MemberLevel").Order("id DESC").Limit(limit).Offset(offset).Find(&members)
+	return members, total, result.Error
+}
+
+// GetMember 获取会员订阅
+func (s *AppMemberService) GetMember(appID, memberID uint) (*models.AppMember, error) {
+	var member models.AppMember
+	if err := config.DB.Preload("MemberLevel").Where("id = ? AND app_id = ?", memberID, appID).First(&member).Error; err != nil {
+		return nil, errors.New("会员不存在")
+	}
+	return &member, nil
+}
+
+// CreateMember 为终端用户创建会员订阅
+func (s *AppMemberService) CreateMember(appID uint, req *models.CreateAppMemberRequest) (*models.AppMember, error) {
+	var app models.Application
+	if err := config.DB.First(&app, appID).Error; err != nil {
+		return nil, errors.New("应用不存在")
+	}
+
+	externalUserID := strings.TrimSpace(req.ExternalUserID)
+	if externalUserID == "" || len(externalUserID) > 128 {
+		return nil, errors.New("用户ID不能为空且不能超过128个字符")
+	}
+
+	member := &models.AppMember{
+		AppID:          appID,
+		ExternalUserID: externalUserID,
+		MemberLevelID:  req.MemberLevelID,
+		StartsAt:       time.Now(),
+		ExpiresAt:      req.ExpiresAt,
+		Status:         req.Status,
+	}
+	if req.StartsAt != nil {
+		member.StartsAt = *req.StartsAt
+	}
+	if member.Status == "" {
+		member.Status = models.AppMemberStatusActive
+	}
+	if err := s.validateMember(member); err != nil {
+		return nil, err
+	}
+
+	var existing models.AppMember
+	if err := config.DB.Where("app_id = ? AND external_user_id = ?", appID, externalUserID).First(&existing).Error; err == nil {
+		return nil, errors.New("该用户已有会员订阅")
+	}
+
+	if err := config.DB.Create(member).Error; err != nil {
+		return nil, err
+	}
+	return s.GetMember(appID, member.ID)
+}
+
+// UpdateMember 更新会员订阅的等级、有效期或状态
+func (s *AppMemberService) UpdateMember(appID, memberID uint, req *models.UpdateAppMemberRequest) (*models.AppMember, error) {
+	member, err := s.GetMember(appID, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.MemberLevelID != nil {
+		member.MemberLevelID = *req.MemberLevelID
+	}
+	if req.StartsAt != nil {
+		member.StartsAt = *req.StartsAt
+	}
+	if req.NeverExpires {
+		member.ExpiresAt = nil
+	} else if req.ExpiresAt != nil {
+		member.ExpiresAt = req.ExpiresAt
+	}
+	if req.Status != nil {
+		member.Status = *req.Status
+	}
+	if err := s.validateMember(member); err != nil {
+		return nil, err
+	}
+
+	err = config.DB.Model(member).Updates(map[string]interface{}{
+		"member_level_id": member.MemberLevelID,
+		"starts_at":       member.StartsAt,
+		"expires_at":      member.ExpiresAt,
+		"status":          member.Status,
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+	return s.GetMember(appID, memberID)
+}
+
+// DeleteMember 删除会员订阅
+func (s *AppMemberService) DeleteMember(appID, memberID uint) error {
+	result := config.DB.Where("id = ? AND app_id = ?", memberID, appID).Delete(&models.AppMember{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("会员不存在")
+	}
+	return nil
+}
+
+// GetMembership 获取终端用户当前的会员信息，订阅生效时附带合并继承后的权限配置
+func (s *AppMemberService) GetMembership(appID uint, externalUserID string) (*models.Membership, error) {
+	var member models.AppMember
+	err := config.DB.Where("app_id = ? AND external_user_id = ?", appID, externalUserID).First(&member).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrMemberNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	membership := &models.Membership{
+		ExternalUserID: member.ExternalUserID,
+		Status:         member.CurrentStatus(now),
+		Active:         member.IsCurrent(now),
+		StartsAt:       member.StartsAt,
+		ExpiresAt:      member.ExpiresAt,
+	}
+	if !membership.Active {
+		return membership, nil
+	}
+
+	levels, err := s.memberService.GetEffectiveMemberLevels(appID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range levels {
+		if levels[i].ID == member.MemberLevelID {
+			membership.Level = &levels[i]
+			return membership, nil
+		}
+	}
+	return nil, errors.New("会员等级不存在")
+}
+
+// CheckEntitlements 根据用户当前的会员等级检查权益，没有生效订阅的用户全部拒绝
+func (s *AppMemberService) CheckEntitlements(appID uint, req *models.EntitlementCheckRequest) (*models.EntitlementCheck, error) {
+	if len(req.Features) == 0 || len(req.Features) > 100 {
+		return nil, errors.New("一次检查的权益数量必须在1-100之间")
+	}
+	for _, feature := range req.Features {
+		if strings.TrimSpace(feature) == "" {
+			return nil, errors.New("权益不能为空")
+		}
+	}
+
+	check := &models.EntitlementCheck{
+		ExternalUserID: req.ExternalUserID,
+		Results:        make([]models.EntitlementResult, 0, len(req.Features)),
+		CheckedAt:      time.Now(),
+	}
+
+	membership, err := s.GetMembership(appID, req.ExternalUserID)
+	if err != nil && !errors.Is(err, ErrMemberNotFound) {
+		return nil, err
+	}
+
+	var doc interface{}
+	if membership != nil && membership.Level != nil {
+		check.Active = true
+		check.Level = &models.MemberLevelSummary{
+			ID:    membership.Level.ID,
+			Name:  membership.Level.Name,
+			Level: membership.Level.Level,
+		}
+		decoder := json.NewDecoder(bytes.NewReader(membership.Level.Permissions))
+		decoder.UseNumber()
+		if err := decoder.Decode(&doc); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, feature := range req.Features {
+		result := models.EntitlementResult{Feature: feature}
+		if check.Active {
+			result.Allowed, result.Value = utils.EvaluateEntitlement(doc, strings.TrimSpace(feature))
+		}
+		check.Results = append(check.Results, result)
+	}
+	return check, nil
+}
+
+// validateMember 校验会员订阅的等级、有效期和状态
+func (s *AppMemberService) validateMember(member *models.AppMember) error {
+	if !models.IsValidAppMemberStatus(member.Status) {
+		return errors.New("无效的会员状态")
+	}
+	if member.ExpiresAt != nil && !member.ExpiresAt.After(member.StartsAt) {
+		return errors.New("到期时间必须晚于开始时间")
+	}
+
+	var level models.MemberLevel
+	err := config.DB.Where("id = ? AND app_id = ?", member.MemberLevelID, member.AppID).First(&level).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.New("会员等级不存在")
+	}
+	return err
+}
@@ -4,13 +4,20 @@ import (
 	"app_management/config"
 	"app_management/models"
 	"app_management/utils"
+	"app_management/utils/semver"
 	"encoding/json"
 	"errors"
-	"regexp"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+// ErrAppNotFound 应用不存在
+var ErrAppNotFound = errors.New("应用不存在")
+
 // AppService 应用服务
 type AppService struct {
 	cacheService *CacheService
@@ -28,9 +35,9 @@ func (s *AppService) GetApplications() ([]models.Application, error) {
 	// 从数据库获取，使用优化的查询
 	var applications []models.Application
 	result := config.DB.
-		Select("id, name, description, status, created_at, updated_at").
+		Select("id, name, description, status, api_key_prefix, public_feed_enabled, created_at, updated_at").
 		Preload("Versions", func(db *gorm.DB) *gorm.DB {
-			return db.Select("id, app_id, version, changelog_md, changelog_html, created_at").
+			return db.Select("id, app_id, version, channel, force_update, breaking, security, rollout_percentage, rollout_status, yanked, yanked_at, yank_reason, publish_status, publish_at, published_at, changelog_md, changelog_html, created_at").
 				Order("created_at DESC")
 		}).
 		Order("created_at DESC").
@@ -62,17 +69,20 @@ func (s *AppService) CreateApplication(name, description string) (*models.Applic
 		return nil, errors.New("生成API密钥失败")
 	}
 
+	// 数据库只保存密钥哈希，明文仅在本次响应中返回
 	app := &models.Application{
-		Name:        name,
-		Description: description,
-		Status:      "active",
-		APIKey:      apiKey,
+		Name:         name,
+		Description:  description,
+		Status:       "active",
+		APIKeyPrefix: utils.APIKeyPrefix(apiKey),
+		APIKeyHash:   utils.HashAPIKey(apiKey),
 	}
 
 	result := config.DB.Create(app)
 	if result.Error != nil {
 		return nil, result.Error
 	}
+	app.APIKey = apiKey
 
 	// 清除应用列表缓存
 	s.cacheService.ClearApplicationCache()
@@ -106,6 +116,137 @@ func (s *AppService) GetApplication(id uint) (*models.Application, error) {
 	return &app, nil
 }
 
+// appStatusTransitions 允许的应用状态流转，deprecated 为终态
+var appStatusTransitions = map[string][]string{
+	models.AppStatusActive:      {models.AppStatusMaintenance, models.AppStatusDeprecated},
+	models.AppStatusMaintenance: {models.AppStatusActive, models.AppStatusDeprecated},
+	models.AppStatusDeprecated:  {},
+}
+
+// canTransitAppStatus 检查应用状态能否从 from 流转到 to
+func canTransitAppStatus(from, to string) bool {
+	if from == to {
+		return true
+	}
+	for _, next := range appStatusTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateApplication 更新应用信息及状态
+func (s *AppService) UpdateApplication(id uint, req *models.UpdateApplicationRequest) (*models.Application, error) {
+	var app models.Application
+	if err := config.DB.First(&app, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAppNotFound
+		}
+		return nil, err
+	}
+
+	if req.Name != nil && *req.Name != app.Name {
+		if len(*req.Name) < 2 || len(*req.Name) > 20 {
+			return nil, errors.New("应用名称长度必须在2-20个字符之间")
+		}
+		var existingApp models.Application
+		if err := config.DB.Where("name = ? AND id <> ?", *req.Name, id).First(&existingApp).Error; err == nil {
+			return nil, errors.New("应用名称已存在")
+		}
+		app.Name = *req.Name
+	}
+
+	if req.Description != nil {
+		if len(*req.Description) > 200 {
+			return nil, errors.New("应用描述不能超过200个字符")
+		}
+		app.Description = *req.Description
+	}
+
+	if req.Status != nil {
+		if _, ok := appStatusTransitions[*req.Status]; !ok {
+			return nil, errors.New("无效的应用状态")
+		}
+		if !canTransitAppStatus(app.Status, *req.Status) {
+			return nil, fmt.Errorf("应用状态不能从 %s 变更为 %s", app.Status, *req.Status)
+		}
+		// 状态变更时重置旧的状态说明，避免维护公告残留到其他状态
+		if *req.Status != app.Status {
+			app.StatusMessage = ""
+		}
+		app.Status = *req.Status
+	}
+
+	if req.MinSupportedVersion != nil {
+		if *req.MinSupportedVersion != "" && !semver.IsValid(*req.MinSupportedVersion) {
+			return nil, errors.New("最低支持版本号格式不正确")
+		}
+		app.MinSupportedVersion = *req.MinSupportedVersion
+	}
+
+	if req.StatusMessage != nil {
+		if len(*req.StatusMessage) > 200 {
+			return nil, errors.New("状态说明不能超过200个字符")
+		}
+		app.StatusMessage = *req.StatusMessage
+	}
+
+	if req.PublicFeedEnabled != nil {
+		app.PublicFeedEnabled = *req.PublicFeedEnabled
+	}
+
+	if err := config.DB.Save(&app).Error; err != nil {
+		return nil, err
+	}
+
+	// 清除相关缓存
+	s.cacheService.ClearApplicationCache()
+	config.DeleteCache("apps:list")
+
+	return &app, nil
+}
+
+// RotateAPIKey 轮换应用的API密钥，旧密钥在宽限期内仍然有效
+func (s *AppService) RotateAPIKey(id uint, gracePeriod time.Duration) (*models.Application, error) {
+	var app models.Application
+	if err := config.DB.First(&app, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAppNotFound
+		}
+		return nil, err
+	}
+
+	apiKey, err := utils.GenerateAPIKey()
+	if err != nil {
+		return nil, errors.New("生成API密钥失败")
+	}
+
+	if gracePeriod > 0 {
+		expiresAt := time.Now().Add(gracePeriod)
+		app.PreviousAPIKeyPrefix = app.APIKeyPrefix
+		app.PreviousAPIKeyHash = app.APIKeyHash
+		app.PreviousAPIKeyExpiresAt = &expiresAt
+	} else {
+		app.PreviousAPIKeyPrefix = ""
+		app.PreviousAPIKeyHash = ""
+		app.PreviousAPIKeyExpiresAt = nil
+	}
+	app.APIKeyPrefix = utils.APIKeyPrefix(apiKey)
+	app.APIKeyHash = utils.HashAPIKey(apiKey)
+
+	if err := config.DB.Save(&app).Error; err != nil {
+		return nil, err
+	}
+	app.APIKey = apiKey
+
+	// 清除相关缓存
+	s.cacheService.ClearApplicationCache()
+	config.DeleteCache("apps:list")
+
+	return &app, nil
+}
+
 // DeleteApplication 删除应用
 func (s *AppService) DeleteApplication(id uint) error {
 	// 检查是否有版本记录
@@ -120,6 +261,14 @@ func (s *AppService) DeleteApplication(id uint) error {
 		return result.Error
 	}
 
+	// 删除应用的命名API密钥、签名密钥、会员等级、权限配置Schema和会员订阅
+	config.DB.Where("app_id = ?", id).Delete(&models.APIKey{})
+	config.DB.Where("app_id = ?", id).Delete(&models.SigningKey{})
+	config.DB.Where("app_id = ?", id).Delete(&models.MemberLevel{})
+	config.DB.Where("app_id = ?", id).Delete(&models.PermissionSchema{})
+	config.DB.Where("app_id = ?", id).Delete(&models.AppMember{})
+	s.cacheService.ClearMemberLevelsCache(strconv.FormatUint(uint64(id), 10))
+
 	// 清除相关缓存
 	s.cacheService.ClearApplicationCache()
 	s.cacheService.ClearVersionCache()
@@ -128,42 +277,144 @@ func (s *AppService) DeleteApplication(id uint) error {
 }
 
 // CreateVersion 创建版本
-func (s *AppService) CreateVersion(appID uint, version, changelogMD string) (*models.Version, error) {
+func (s *AppService) CreateVersion(appID uint, req *models.CreateVersionRequest) (*models.Version, error) {
 	// 验证版本号格式
-	semverRegex := regexp.MustCompile(`^\d+\.\d+\.\d+$`)
-	if !semverRegex.MatchString(version) {
-		return nil, errors.New("版本号格式不正确，应为 x.y.z 格式")
+	newSemver, err := semver.Parse(req.Version)
+	if err != nil {
+		return nil, errors.New("版本号格式不正确，应为语义化版本格式，如 1.2.3、2.0.0-beta.1")
+	}
+	if len(req.Version) > 64 {
+		return nil, errors.New("版本号不能超过64个字符")
+	}
+
+	channel := req.Channel
+	if channel == "" {
+		channel = models.ChannelStable
+	}
+	if models.ChannelRank(channel) < 0 {
+		return nil, errors.New("无效的发布渠道")
+	}
+
+	rolloutPercentage := 100
+	if req.RolloutPercentage != nil {
+		if *req.RolloutPercentage < 0 || *req.RolloutPercentage > 100 {
+			return nil, errors.New("灰度比例必须在0-100之间")
+		}
+		rolloutPercentage = *req.RolloutPercentage
+	}
+
+	publishStatus := req.PublishStatus
+	if publishStatus == "" {
+		publishStatus = models.PublishStatusPublished
+		if req.PublishAt != nil {
+			publishStatus = models.PublishStatusScheduled
+		}
+	}
+	var publishedAt *time.Time
+	switch publishStatus {
+	case models.PublishStatusPublished:
+		now := time.Now()
+		publishedAt = &now
+	case models.PublishStatusScheduled:
+		if req.PublishAt == nil || !req.PublishAt.After(time.Now()) {
+			return nil, errors.New("定时发布时间必须晚于当前时间")
+		}
+	case models.PublishStatusDraft:
+	default:
+		return nil, errors.New("无效的发布状态")
+	}
+
+	// 结构化条目存在时由条目生成更新日志，包含安全条目的版本标记为安全更新
+	entries, err := buildChangelogEntries(req.Entries)
+	if err != nil {
+		return nil, err
+	}
+	changelogs, err := buildLocalizedChangelogs(req.Changelogs)
+	if err != nil {
+		return nil, err
+	}
+	changelogMD := req.ChangelogMD
+	security := req.Security
+	if len(entries) > 0 {
+		changelogMD = models.ChangelogMarkdown(entries)
+		security = security || hasSecurityEntry(entries)
 	}
 
 	// 检查应用是否存在
 	var app models.Application
 	if err := config.DB.First(&app, appID).Error; err != nil {
-		return nil, errors.New("应用不存在")
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAppNotFound
+		}
+		return nil, err
 	}
 
 	// 检查版本是否已存在
 	var existingVersion models.Version
-	if err := config.DB.Where("app_id = ? AND version = ?", appID, version).First(&existingVersion).Error; err == nil {
+	if err := config.DB.Where("app_id = ? AND version = ?", appID, req.Version).First(&existingVersion).Error; err == nil {
 		return nil, errors.New("版本号已存在")
 	}
 
+	// 新版本必须高于该渠道可见的最新版本，除非显式允许
+	if !req.AllowRegression {
+		latest, err := s.GetLatestVersion(appID, channel)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if latest != nil && !newSemver.GreaterThan(semver.MustParse(latest.Version)) {
+			return nil, fmt.Errorf("版本号必须高于当前最新版本 %s", latest.Version)
+		}
+	}
+
 	// 创建版本记录
 	newVersion := &models.Version{
 		AppID:       appID,
-		Version:     version,
+		Version:     req.Version,
+		Channel:     channel,
+		ForceUpdate: req.ForceUpdate,
+		Breaking:    req.Breaking,
+		Security:    security,
 		ChangelogMD: changelogMD,
-		// 简单的Markdown转HTML
-		ChangelogHTML: changelogMD,
+		// 渲染并清理Markdown
+		ChangelogHTML:     utils.RenderMarkdown(changelogMD),
+		RolloutPercentage: rolloutPercentage,
+		RolloutStatus:     models.RolloutStatusActive,
+		PublishStatus:     publishStatus,
+		PublishedAt:       publishedAt,
+	}
+	if publishStatus == models.PublishStatusScheduled {
+		newVersion.PublishAt = req.PublishAt
 	}
 
-	result := config.DB.Create(newVersion)
-	if result.Error != nil {
-		return nil, result.Error
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(newVersion).Error; err != nil {
+			return err
+		}
+		// 灰度比例为0时 GORM 会使用列默认值100，需要在同一事务内写入
+		if rolloutPercentage == 0 {
+			if err := tx.Model(newVersion).Update("rollout_percentage", 0).Error; err != nil {
+				return err
+			}
+		}
+		if err := replaceChangelogEntries(tx, newVersion.ID, entries); err != nil {
+			return err
+		}
+		return replaceLocalizedChangelogs(tx, newVersion.ID, changelogs)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) > 0 {
+		newVersion.Entries = entries
+	}
+	if len(changelogs) > 0 {
+		newVersion.Changelogs = changelogs
 	}
 
 	// 更新应用的最新版本
-	app.LatestVersion = version
-	config.DB.Save(&app)
+	if err := s.refreshLatestVersion(appID); err != nil {
+		return nil, err
+	}
 
 	// 清除相关缓存
 	s.cacheService.ClearApplicationCache()
@@ -172,6 +423,440 @@ func (s *AppService) CreateVersion(appID uint, version, changelogMD string) (*mo
 	return newVersion, nil
 }
 
+// GetLatestVersion 按语义化版本优先级获取应用在指定渠道的最新版本
+// 订阅较不稳定渠道的客户端同样可以获取更稳定渠道的版本
+func (s *AppService) GetLatestVersion(appID uint, channel string) (*models.Version, error) {
+	channels := models.VisibleChannels(channel)
+	if channels == nil {
+		return nil, errors.New("无效的发布渠道")
+	}
+
+	var versions []models.Version
+	if err := config.DB.Where("app_id = ? AND channel IN ?", appID, channels).Find(&versions).Error; err != nil {
+		return nil, err
+	}
+
+	latest := latestVersion(versions)
+	if latest == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return latest, nil
+}
+
+// PromoteVersion 将版本提升到更稳定的发布渠道，如从 beta 提升到 stable
+func (s *AppService) PromoteVersion(appID, versionID uint, channel string) (*models.Version, error) {
+	targetRank := models.ChannelRank(channel)
+	if targetRank < 0 {
+		return nil, errors.New("无效的发布渠道")
+	}
+
+	var version models.Version
+	if err := config.DB.Where("id = ? AND app_id = ?", versionID, appID).First(&version).Error; err != nil {
+		return nil, errors.New("版本不存在")
+	}
+
+	if targetRank >= models.ChannelRank(version.Channel) {
+		return nil, fmt.Errorf("版本只能从 %s 提升到更稳定的渠道", version.Channel)
+	}
+
+	version.Channel = channel
+	if err := config.DB.Model(&version).Update("channel", channel).Error; err != nil {
+		return nil, err
+	}
+
+	// 更新应用的最新版本
+	if err := s.refreshLatestVersion(appID); err != nil {
+		return nil, err
+	}
+
+	// 清除相关缓存
+	s.cacheService.ClearApplicationCache()
+	s.cacheService.ClearVersionCache()
+
+	return &version, nil
+}
+
+// PublishVersion 立即发布草稿或定时版本
+func (s *AppService) PublishVersion(appID, versionID uint) (*models.Version, error) {
+	var version models.Version
+	if err := config.DB.Where("id = ? AND app_id = ?", versionID, appID).First(&version).Error; err != nil {
+		return nil, errors.New("版本不存在")
+	}
+
+	if version.IsPublished() {
+		return nil, errors.New("版本已发布")
+	}
+
+	if _, err := s.publish(&version); err != nil {
+		return nil, err
+	}
+
+	return &version, nil
+}
+
+// ScheduleVersion 设置草稿或定时版本的发布时间
+func (s *AppService) ScheduleVersion(appID, versionID uint, publishAt time.Time) (*models.Version, error) {
+	if !publishAt.After(time.Now()) {
+		return nil, errors.New("定时发布时间必须晚于当前时间")
+	}
+
+	var version models.Version
+	if err := config.DB.Where("id = ? AND app_id = ?", versionID, appID).First(&version).Error; err != nil {
+		return nil, errors.New("版本不存在")
+	}
+
+	if version.IsPublished() {
+		return nil, errors.New("版本已发布")
+	}
+
+	version.PublishStatus = models.PublishStatusScheduled
+	version.PublishAt = &publishAt
+	if err := config.DB.Model(&version).Updates(map[string]interface{}{
+		"publish_status": version.PublishStatus,
+		"publish_at":     version.PublishAt,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	// 清除相关缓存
+	s.cacheService.ClearVersionCache()
+
+	return &version, nil
+}
+
+// PublishDueVersions 发布所有已到发布时间的定时版本，返回本次发布的版本
+// 单个版本发布失败时记录日志并继续处理其余版本
+func (s *AppService) PublishDueVersions() ([]models.Version, error) {
+	var due []models.Version
+	if err := config.DB.Where("publish_status = ? AND publish_at <= ?", models.PublishStatusScheduled, time.Now()).
+		Find(&due).Error; err != nil {
+		return nil, err
+	}
+
+	published := make([]models.Version, 0, len(due))
+	for i := range due {
+		ok, err := s.publish(&due[i])
+		if err != nil {
+			log.Printf("定时发布版本 %s (应用ID %d) 失败: %v", due[i].Version, due[i].AppID, err)
+		}
+		if ok {
+			published = append(published, due[i])
+		}
+	}
+	return published, nil
+}
+
+// publish 将版本标记为已发布并重新计算最新版本
+// 仅在版本仍未发布时更新，多个实例同时执行时只有一个会成功，返回是否由本次调用发布
+func (s *AppService) publish(version *models.Version) (bool, error) {
+	now := time.Now()
+	result := config.DB.Model(&models.Version{}).
+		Where("id = ? AND publish_status <> ?", version.ID, models.PublishStatusPublished).
+		Updates(map[string]interface{}{
+			"publish_status": models.PublishStatusPublished,
+			"published_at":   now,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	version.PublishStatus = models.PublishStatusPublished
+	version.PublishedAt = &now
+
+	if err := s.refreshLatestVersion(version.AppID); err != nil {
+		return true, err
+	}
+
+	// 清除相关缓存
+	s.cacheService.ClearApplicationCache()
+	s.cacheService.ClearVersionCache()
+
+	return true, nil
+}
+
+// UpdateVersion 更新版本的更新日志或强制更新标记
+func (s *AppService) UpdateVersion(appID, versionID uint, req *models.UpdateVersionRequest) (*models.Version, error) {
+	var version models.Version
+	if err := config.DB.Where("id = ? AND app_id = ?", versionID, appID).First(&version).Error; err != nil {
+		return nil, errors.New("版本不存在")
+	}
+
+	var entries []models.ChangelogEntry
+	if req.Entries != nil {
+		var err error
+		if entries, err = buildChangelogEntries(*req.Entries); err != nil {
+			return nil, err
+		}
+	}
+	// 只提供 Markdown 时以其为准，清除原有的结构化条目，避免两者不一致
+	replaceEntries := req.Entries != nil || req.ChangelogMD != nil
+	var changelogs []models.LocalizedChangelog
+	if req.Changelogs != nil {
+		var err error
+		if changelogs, err = buildLocalizedChangelogs(req.Changelogs); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.ChangelogMD != nil {
+		version.ChangelogMD = *req.ChangelogMD
+	}
+	if req.ForceUpdate != nil {
+		version.ForceUpdate = *req.ForceUpdate
+	}
+	if req.Breaking != nil {
+		version.Breaking = *req.Breaking
+	}
+	if req.Security != nil {
+		version.Security = *req.Security
+	}
+	// 结构化条目存在时由条目生成更新日志
+	if len(entries) > 0 {
+		version.ChangelogMD = models.ChangelogMarkdown(entries)
+		version.Security = version.Security || hasSecurityEntry(entries)
+	}
+	version.ChangelogHTML = utils.RenderMarkdown(version.ChangelogMD)
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&version).Updates(map[string]interface{}{
+			"changelog_md":   version.ChangelogMD,
+			"changelog_html": version.ChangelogHTML,
+			"force_update":   version.ForceUpdate,
+			"breaking":       version.Breaking,
+			"security":       version.Security,
+		}).Error; err != nil {
+			return err
+		}
+		if replaceEntries {
+			if err := replaceChangelogEntries(tx, version.ID, entries); err != nil {
+				return err
+			}
+		}
+		if req.Changelogs != nil {
+			return replaceLocalizedChangelogs(tx, version.ID, changelogs)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if replaceEntries {
+		version.Entries = entries
+	}
+	if req.Changelogs != nil {
+		version.Changelogs = changelogs
+	}
+
+	// 清除相关缓存
+	s.cacheService.ClearApplicationCache()
+	s.cacheService.ClearVersionCache()
+
+	return &version, nil
+}
+
+// YankVersion 撤回版本，客户端不再获取该版本，但保留版本记录
+func (s *AppService) YankVersion(appID, versionID uint, reason string) (*models.Version, error) {
+	if len(reason) > 200 {
+		return nil, errors.New("撤回原因不能超过200个字符")
+	}
+
+	var version models.Version
+	if err := config.DB.Where("id = ? AND app_id = ?", versionID, appID).First(&version).Error; err != nil {
+		return nil, errors.New("版本不存在")
+	}
+
+	now := time.Now()
+	version.Yanked = true
+	version.YankedAt = &now
+	version.YankReason = reason
+	if err := s.saveYankState(&version); err != nil {
+		return nil, err
+	}
+
+	return &version, nil
+}
+
+// UnyankVersion 恢复已撤回的版本
+func (s *AppService) UnyankVersion(appID, versionID uint) (*models.Version, error) {
+	var version models.Version
+	if err := config.DB.Where("id = ? AND app_id = ?", versionID, appID).First(&version).Error; err != nil {
+		return nil, errors.New("版本不存在")
+	}
+
+	version.Yanked = false
+	version.YankedAt = nil
+	version.YankReason = ""
+	if err := s.saveYankState(&version); err != nil {
+		return nil, err
+	}
+
+	return &version, nil
+}
+
+// saveYankState 保存版本的撤回状态并重新计算最新版本
+func (s *AppService) saveYankState(version *models.Version) error {
+	if err := config.DB.Model(version).Updates(map[string]interface{}{
+		"yanked":      version.Yanked,
+		"yanked_at":   version.YankedAt,
+		"yank_reason": version.YankReason,
+	}).Error; err != nil {
+		return err
+	}
+
+	if err := s.refreshLatestVersion(version.AppID); err != nil {
+		return err
+	}
+
+	// 清除相关缓存
+	s.cacheService.ClearApplicationCache()
+	s.cacheService.ClearVersionCache()
+
+	return nil
+}
+
+// DeleteVersion 删除版本及其更新日志和构建产物
+func (s *AppService) DeleteVersion(appID, versionID uint) error {
+	var artifacts []models.VersionArtifact
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND app_id = ?", versionID, appID).Delete(&models.Version{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("版本不存在")
+		}
+
+		// 同时删除版本的更新日志条目和多语言更新日志
+		if err := tx.Where("version_id = ?", versionID).Delete(&models.ChangelogEntry{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("version_id = ?", versionID).Delete(&models.LocalizedChangelog{}).Error; err != nil {
+			return err
+		}
+
+		// 同时删除版本的构建产物
+		if err := tx.Where("version_id = ?", versionID).Find(&artifacts).Error; err != nil {
+			return err
+		}
+		return tx.Where("version_id = ?", versionID).Delete(&models.VersionArtifact{}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	// 提交后再删除托管文件
+	artifactService := NewArtifactService()
+	for _, artifact := range artifacts {
+		if artifact.IsHosted() {
+			artifactService.deleteObject(artifact.StorageKey)
+		}
+	}
+
+	if err := s.refreshLatestVersion(appID); err != nil {
+		return err
+	}
+
+	// 清除相关缓存
+	s.cacheService.ClearApplicationCache()
+	s.cacheService.ClearVersionCache()
+
+	return nil
+}
+
+// UpdateRollout 调整版本的灰度比例或状态
+func (s *AppService) UpdateRollout(appID, versionID uint, req *models.UpdateRolloutRequest) (*models.Version, error) {
+	var version models.Version
+	if err := config.DB.Where("id = ? AND app_id = ?", versionID, appID).First(&version).Error; err != nil {
+		return nil, errors.New("版本不存在")
+	}
+
+	if version.RolloutStatus == models.RolloutStatusHalted {
+		return nil, errors.New("已终止的灰度发布无法调整")
+	}
+
+	previousPercentage := version.RolloutPercentage
+	if req.Percentage != nil {
+		if *req.Percentage < 0 || *req.Percentage > 100 {
+			return nil, errors.New("灰度比例必须在0-100之间")
+		}
+		version.RolloutPercentage = *req.Percentage
+	}
+
+	if req.Status != nil {
+		switch *req.Status {
+		case models.RolloutStatusActive, models.RolloutStatusPaused, models.RolloutStatusHalted:
+			version.RolloutStatus = *req.Status
+		default:
+			return nil, errors.New("无效的灰度状态")
+		}
+	}
+
+	// 暂停期间只保留已推送的客户端，不能扩大灰度比例
+	if version.RolloutStatus == models.RolloutStatusPaused && version.RolloutPercentage > previousPercentage {
+		return nil, errors.New("暂停的灰度发布不能扩大比例，请先恢复推送")
+	}
+
+	if err := config.DB.Model(&version).Updates(map[string]interface{}{
+		"rollout_percentage": version.RolloutPercentage,
+		"rollout_status":     version.RolloutStatus,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	// 全量发布或终止后最新版本可能变化
+	if err := s.refreshLatestVersion(appID); err != nil {
+		return nil, err
+	}
+
+	// 清除相关缓存
+	s.cacheService.ClearApplicationCache()
+	s.cacheService.ClearVersionCache()
+
+	return &version, nil
+}
+
+// refreshLatestVersion 重新计算并保存应用已全量发布的最新稳定版本号
+func (s *AppService) refreshLatestVersion(appID uint) error {
+	var versions []models.Version
+	if err := config.DB.Where("app_id = ? AND channel = ?", appID, models.ChannelStable).Find(&versions).Error; err != nil {
+		return err
+	}
+
+	var released []models.Version
+	for _, v := range versions {
+		if v.IsPublished() && !v.Yanked && v.IsFullyRolledOut() {
+			released = append(released, v)
+		}
+	}
+
+	latestVersionNumber := ""
+	if latest := latestVersion(released); latest != nil {
+		latestVersionNumber = latest.Version
+	}
+
+	return config.DB.Model(&models.Application{}).Where("id = ?", appID).
+		Update("latest_version", latestVersionNumber).Error
+}
+
+// latestVersion 返回语义化版本优先级最高的版本，忽略无法解析的版本号
+func latestVersion(versions []models.Version) *models.Version {
+	var latest *models.Version
+	var latestSemver *semver.Version
+	for i := range versions {
+		v, err := semver.Parse(versions[i].Version)
+		if err != nil {
+			continue
+		}
+		if latestSemver == nil || v.GreaterThan(latestSemver) {
+			latest, latestSemver = &versions[i], v
+		}
+	}
+	return latest
+}
+
 // GetVersions 获取版本列表
 func (s *AppService) GetVersions(appID uint) ([]models.Version, error) {
 	// 尝试从缓存获取
@@ -186,7 +871,13 @@ func (s *AppService) GetVersions(appID uint) ([]models.Version, error) {
 	// 从数据库获取，使用优化的查询
 	var versions []models.Version
 	result := config.DB.
-		Select("id, app_id, version, changelog_md, changelog_html, created_at").
+		Select("id, app_id, version, channel, force_update, breaking, security, rollout_percentage, rollout_status, yanked, yanked_at, yank_reason, publish_status, publish_at, published_at, changelog_md, changelog_html, created_at").
+		Preload("Entries", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		Preload("Changelogs", func(db *gorm.DB) *gorm.DB {
+			return db.Order("locale ASC")
+		}).
 		Where("app_id = ?", appID).
 		Order("created_at DESC").
 		Find(&versions)
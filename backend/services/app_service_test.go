@@ -1,9 +1,20 @@
 package services
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"app_management/config"
+	"app_management/models"
+	"app_management/storage"
+	"app_management/utils"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
@@ -30,8 +41,11 @@ func (suite *AppServiceTestSuite) TearDownSuite() {
 // SetupTest 设置单个测试
 func (suite *AppServiceTestSuite) SetupTest() {
 	// 清理测试数据
+	config.DB.Exec("DELETE FROM api_keys")
 	config.DB.Exec("DELETE FROM versions")
 	config.DB.Exec("DELETE FROM applications")
+	config.DB.Exec("DELETE FROM app_members")
+	config.DB.Exec("DELETE FROM member_levels")
 }
 
 // TestCreateApplication 测试创建应用
@@ -44,6 +58,15 @@ func (suite *AppServiceTestSuite) TestCreateApplication() {
 	assert.Equal(suite.T(), "这是一个测试应用", app.Description)
 	assert.Equal(suite.T(), "active", app.Status)
 
+	// 明文密钥只在创建时返回，数据库保存哈希
+	assert.Len(suite.T(), app.APIKey, 64)
+	assert.Equal(suite.T(), app.APIKey[:utils.APIKeyPrefixLength], app.APIKeyPrefix)
+	assert.True(suite.T(), utils.VerifyAPIKey(app.APIKey, app.APIKeyHash))
+
+	retrievedApp, err := suite.appService.GetApplication(app.ID)
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), retrievedApp.APIKey)
+
 	// 测试重复名称
 	_, err = suite.appService.CreateApplication("测试应用", "重复名称")
 	assert.Error(suite.T(), err)
@@ -71,6 +94,11 @@ func (suite *AppServiceTestSuite) TestGetApplications() {
 	assert.Len(suite.T(), apps, 2)
 	assert.Equal(suite.T(), "应用1", apps[0].Name)
 	assert.Equal(suite.T(), "应用2", apps[1].Name)
+
+	// 列表中包含密钥前缀，便于识别密钥
+	for _, app := range apps {
+		assert.NotEmpty(suite.T(), app.APIKeyPrefix)
+	}
 }
 
 // TestGetApplication 测试获取单个应用
@@ -89,6 +117,79 @@ func (suite *AppServiceTestSuite) TestGetApplication() {
 	assert.Error(suite.T(), err)
 }
 
+// TestUpdateApplication 测试更新应用
+func (suite *AppServiceTestSuite) TestUpdateApplication() {
+	app, _ := suite.appService.CreateApplication("更新测试应用", "原始描述")
+
+	// 测试更新名称和描述
+	name := "新名称应用"
+	description := "新的描述"
+	updatedApp, err := suite.appService.UpdateApplication(app.ID, &models.UpdateApplicationRequest{
+		Name:        &name,
+		Description: &description,
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "新名称应用", updatedApp.Name)
+	assert.Equal(suite.T(), "新的描述", updatedApp.Description)
+
+	// 测试进入维护状态
+	status := models.AppStatusMaintenance
+	notice := "系统升级中"
+	updatedApp, err = suite.appService.UpdateApplication(app.ID, &models.UpdateApplicationRequest{
+		Status:        &status,
+		StatusMessage: &notice,
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), models.AppStatusMaintenance, updatedApp.Status)
+	assert.Equal(suite.T(), "系统升级中", updatedApp.StatusMessage)
+
+	// 测试停用后无法恢复
+	status = models.AppStatusDeprecated
+	_, err = suite.appService.UpdateApplication(app.ID, &models.UpdateApplicationRequest{Status: &status})
+	assert.NoError(suite.T(), err)
+
+	status = models.AppStatusActive
+	_, err = suite.appService.UpdateApplication(app.ID, &models.UpdateApplicationRequest{Status: &status})
+	assert.Error(suite.T(), err)
+	assert.Contains(suite.T(), err.Error(), "应用状态不能从")
+
+	// 测试无效状态
+	status = "unknown"
+	_, err = suite.appService.UpdateApplication(app.ID, &models.UpdateApplicationRequest{Status: &status})
+	assert.Error(suite.T(), err)
+	assert.Contains(suite.T(), err.Error(), "无效的应用状态")
+
+	// 应用不存在
+	_, err = suite.appService.UpdateApplication(99999, &models.UpdateApplicationRequest{Status: &status})
+	assert.ErrorIs(suite.T(), err, ErrAppNotFound)
+	_, err = suite.appService.CreateVersion(99999, &models.CreateVersionRequest{Version: "1.0.0"})
+	assert.ErrorIs(suite.T(), err, ErrAppNotFound)
+}
+
+// TestRotateAPIKey 测试轮换API密钥
+func (suite *AppServiceTestSuite) TestRotateAPIKey() {
+	app, _ := suite.appService.CreateApplication("密钥轮换应用", "测试密钥轮换")
+	oldKey := app.APIKey
+
+	// 带宽限期轮换，旧密钥被保留
+	rotatedApp, err := suite.appService.RotateAPIKey(app.ID, time.Hour)
+	assert.NoError(suite.T(), err)
+	assert.NotEqual(suite.T(), oldKey, rotatedApp.APIKey)
+	assert.True(suite.T(), utils.VerifyAPIKey(rotatedApp.APIKey, rotatedApp.APIKeyHash))
+	assert.True(suite.T(), utils.VerifyAPIKey(oldKey, rotatedApp.PreviousAPIKeyHash))
+	assert.NotNil(suite.T(), rotatedApp.PreviousAPIKeyExpiresAt)
+
+	// 无宽限期轮换，旧密钥立即失效
+	rotatedApp, err = suite.appService.RotateAPIKey(app.ID, 0)
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), rotatedApp.PreviousAPIKeyHash)
+	assert.Nil(suite.T(), rotatedApp.PreviousAPIKeyExpiresAt)
+
+	// 应用不存在
+	_, err = suite.appService.RotateAPIKey(99999, time.Hour)
+	assert.ErrorIs(suite.T(), err, ErrAppNotFound)
+}
+
 // TestDeleteApplication 测试删除应用
 func (suite *AppServiceTestSuite) TestDeleteApplication() {
 	// 创建测试应用
@@ -109,28 +210,943 @@ func (suite *AppServiceTestSuite) TestCreateVersion() {
 	app, _ := suite.appService.CreateApplication("版本测试应用", "测试版本功能")
 
 	// 测试正常创建版本
-	version, err := suite.appService.CreateVersion(app.ID, "1.0.0", "初始版本")
+	version, err := suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{Version: "1.0.0", ChangelogMD: "初始版本"})
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), "1.0.0", version.Version)
 	assert.Equal(suite.T(), "初始版本", version.ChangelogMD)
+	assert.Equal(suite.T(), "<p>初始版本</p>\n", version.ChangelogHTML)
 
 	// 测试版本号格式验证
-	_, err = suite.appService.CreateVersion(app.ID, "invalid", "无效版本号")
+	_, err = suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{Version: "invalid", ChangelogMD: "无效版本号"})
 	assert.Error(suite.T(), err)
 	assert.Contains(suite.T(), err.Error(), "版本号格式")
 
 	// 测试重复版本号
-	_, err = suite.appService.CreateVersion(app.ID, "1.0.0", "重复版本")
+	_, err = suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{Version: "1.0.0", ChangelogMD: "重复版本"})
 	assert.Error(suite.T(), err)
 	assert.Contains(suite.T(), err.Error(), "版本号已存在")
+
+	// 测试预发布版本和构建元数据
+	version, err = suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{Version: "2.0.0-beta.1", ChangelogMD: "测试版"})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "2.0.0-beta.1", version.Version)
+
+	_, err = suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{Version: "2.0.0+build.77", ChangelogMD: "正式版"})
+	assert.NoError(suite.T(), err)
+
+	// 测试版本回退
+	_, err = suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{Version: "1.5.0", ChangelogMD: "旧版本修复"})
+	assert.Error(suite.T(), err)
+	assert.Contains(suite.T(), err.Error(), "版本号必须高于当前最新版本")
+
+	_, err = suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{Version: "1.5.0", ChangelogMD: "旧版本修复", AllowRegression: true})
+	assert.NoError(suite.T(), err)
+
+	// 最新版本按语义化版本优先级计算
+	latest, err := suite.appService.GetLatestVersion(app.ID, models.ChannelStable)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "2.0.0+build.77", latest.Version)
+}
+
+// TestVersionChannels 测试发布渠道
+func (suite *AppServiceTestSuite) TestVersionChannels() {
+	app, _ := suite.appService.CreateApplication("渠道测试应用", "测试发布渠道")
+	suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{Version: "1.0.0", ChangelogMD: "正式版"})
+	beta, err := suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{Version: "1.1.0-beta.1", ChangelogMD: "测试版", Channel: models.ChannelBeta})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), models.ChannelBeta, beta.Channel)
+
+	// 稳定渠道的补丁版本不受 beta 版本影响
+	_, err = suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{Version: "1.0.1", ChangelogMD: "补丁"})
+	assert.NoError(suite.T(), err)
+
+	// 无效渠道
+	_, err = suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{Version: "1.2.0", Channel: "alpha"})
+	assert.Error(suite.T(), err)
+
+	latest, _ := suite.appService.GetLatestVersion(app.ID, models.ChannelStable)
+	assert.Equal(suite.T(), "1.0.1", latest.Version)
+	latest, _ = suite.appService.GetLatestVersion(app.ID, models.ChannelBeta)
+	assert.Equal(suite.T(), "1.1.0-beta.1", latest.Version)
+
+	// 提升到 stable 后应用最新版本随之更新
+	promoted, err := suite.appService.PromoteVersion(app.ID, beta.ID, models.ChannelStable)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), models.ChannelStable, promoted.Channel)
+
+	retrievedApp, _ := suite.appService.GetApplication(app.ID)
+	assert.Equal(suite.T(), "1.1.0-beta.1", retrievedApp.LatestVersion)
+
+	// 不能降级到不稳定的渠道
+	_, err = suite.appService.PromoteVersion(app.ID, beta.ID, models.ChannelNightly)
+	assert.Error(suite.T(), err)
+}
+
+// TestCheckUpdate 测试客户端更新检查
+func (suite *AppServiceTestSuite) TestCheckUpdate() {
+	app, _ := suite.appService.CreateApplication("更新检查应用", "测试更新检查")
+	suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{Version: "1.0.0", ChangelogMD: "初始版本"})
+	suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{Version: "1.1.0", ChangelogMD: "安全修复", ForceUpdate: true})
+	suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{Version: "1.2.0", ChangelogMD: "新功能"})
+
+	updateService := NewUpdateService()
+
+	// 跳过强制更新版本的客户端必须更新
+	result, err := updateService.CheckUpdate(app, &models.UpdateCheckRequest{Current: "1.0.0", Platform: "android"})
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.UpdateAvailable)
+	assert.True(suite.T(), result.Mandatory)
+	assert.Equal(suite.T(), "1.2.0", result.LatestVersion)
+	assert.Len(suite.T(), result.Versions, 2)
+	assert.Equal(suite.T(), "1.2.0", result.Versions[0].Version)
+	assert.Contains(suite.T(), result.Changelog, "安全修复")
+	assert.Contains(suite.T(), result.Changelog, "新功能")
+
+	// 已安装强制更新版本后为可选更新
+	result, err = updateService.CheckUpdate(app, &models.UpdateCheckRequest{Current: "1.1.0"})
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.UpdateAvailable)
+	assert.False(suite.T(), result.Mandatory)
+
+	// 低于最低支持版本时必须更新
+	minSupported := "1.2.0"
+	app, _ = suite.appService.UpdateApplication(app.ID, &models.UpdateApplicationRequest{MinSupportedVersion: &minSupported})
+	result, _ = updateService.CheckUpdate(app, &models.UpdateCheckRequest{Current: "1.1.0"})
+	assert.True(suite.T(), result.Mandatory)
+
+	// 已是最新版本
+	result, err = updateService.CheckUpdate(app, &models.UpdateCheckRequest{Current: "1.2.0"})
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), result.UpdateAvailable)
+	assert.Empty(suite.T(), result.Versions)
+
+	// 无效的版本号
+	_, err = updateService.CheckUpdate(app, &models.UpdateCheckRequest{Current: "abc"})
+	assert.Error(suite.T(), err)
+}
+
+// TestStagedRollout 测试灰度发布
+func (suite *AppServiceTestSuite) TestStagedRollout() {
+	app, _ := suite.appService.CreateApplication("灰度测试应用", "测试灰度发布")
+	suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{Version: "1.0.0", ChangelogMD: "初始版本"})
+
+	zero := 0
+	version, err := suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{Version: "1.1.0", RolloutPercentage: &zero})
+	assert.NoError(suite.T(), err)
+
+	var stored models.Version
+	config.DB.First(&stored, version.ID)
+	assert.Equal(suite.T(), 0, stored.RolloutPercentage)
+
+	updateService := NewUpdateService()
+
+	// 灰度比例为0时所有客户端都获取不到
+	latest, err := updateService.GetLatestForClient(app.ID, models.ChannelStable, "device-1")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "1.0.0", latest.Version)
+
+	retrievedApp, _ := suite.appService.GetApplication(app.ID)
+	assert.Equal(suite.T(), "1.0.0", retrievedApp.LatestVersion)
+
+	// 50%灰度时按客户端确定性分桶
+	fifty := 50
+	_, err = suite.appService.UpdateRollout(app.ID, version.ID, &models.UpdateRolloutRequest{Percentage: &fifty})
+	assert.NoError(suite.T(), err)
+
+	rolledOut := 0
+	for i := 0; i < 200; i++ {
+		clientID := fmt.Sprintf("device-%d", i)
+		latest, _ := updateService.GetLatestForClient(app.ID, models.ChannelStable, clientID)
+		if latest.Version == "1.1.0" {
+			rolledOut++
+			assert.Less(suite.T(), rolloutBucket(app.ID, "1.1.0", clientID), 50)
+		}
+	}
+	assert.Greater(suite.T(), rolledOut, 50)
+	assert.Less(suite.T(), rolledOut, 150)
+
+	// 没有客户端ID时只能获取全量版本
+	latest, _ = updateService.GetLatestForClient(app.ID, models.ChannelStable, "")
+	assert.Equal(suite.T(), "1.0.0", latest.Version)
+
+	active := models.RolloutStatusActive
+
+	// 暂停后已分到的客户端仍可获取，其余客户端仍获取不到，且不能扩大比例
+	paused := models.RolloutStatusPaused
+	_, err = suite.appService.UpdateRollout(app.ID, version.ID, &models.UpdateRolloutRequest{Status: &paused})
+	assert.NoError(suite.T(), err)
+	for i := 0; i < 200; i++ {
+		clientID := fmt.Sprintf("device-%d", i)
+		latest, _ := updateService.GetLatestForClient(app.ID, models.ChannelStable, clientID)
+		assert.Equal(suite.T(), rolloutBucket(app.ID, "1.1.0", clientID) < 50, latest.Version == "1.1.0", clientID)
+	}
+	seventy := 70
+	_, err = suite.appService.UpdateRollout(app.ID, version.ID, &models.UpdateRolloutRequest{Percentage: &seventy})
+	assert.Error(suite.T(), err)
+	_, err = suite.appService.UpdateRollout(app.ID, version.ID, &models.UpdateRolloutRequest{Status: &active, Percentage: &seventy})
+	assert.NoError(suite.T(), err)
+
+	// 全量后更新应用最新版本
+	hundred := 100
+	_, err = suite.appService.UpdateRollout(app.ID, version.ID, &models.UpdateRolloutRequest{Percentage: &hundred})
+	assert.NoError(suite.T(), err)
+	retrievedApp, _ = suite.appService.GetApplication(app.ID)
+	assert.Equal(suite.T(), "1.1.0", retrievedApp.LatestVersion)
+
+	// 终止后不再推送且不可恢复
+	halted := models.RolloutStatusHalted
+	_, err = suite.appService.UpdateRollout(app.ID, version.ID, &models.UpdateRolloutRequest{Status: &halted})
+	assert.NoError(suite.T(), err)
+	latest, _ = updateService.GetLatestForClient(app.ID, models.ChannelStable, "device-1")
+	assert.Equal(suite.T(), "1.0.0", latest.Version)
+
+	_, err = suite.appService.UpdateRollout(app.ID, version.ID, &models.UpdateRolloutRequest{Status: &active})
+	assert.Error(suite.T(), err)
+}
+
+// TestEditYankDeleteVersion 测试编辑、撤回和删除版本
+func (suite *AppServiceTestSuite) TestEditYankDeleteVersion() {
+	app, _ := suite.appService.CreateApplication("版本维护应用", "测试版本维护")
+	suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{
+		Version:    "1.0.0",
+		Entries:    []models.ChangelogEntryRequest{{Category: models.ChangelogAdded, Description: "初始版本"}},
+		Changelogs: map[string]string{"en-US": "Initial release"},
+	})
+	broken, _ := suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{Version: "1.1.0", ChangelogMD: "新功嫩"})
+
+	// 修正更新日志
+	changelog := "新功能"
+	updated, err := suite.appService.UpdateVersion(app.ID, broken.ID, &models.UpdateVersionRequest{ChangelogMD: &changelog})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "新功能", updated.ChangelogMD)
+	assert.Contains(suite.T(), updated.ChangelogHTML, "新功能")
+
+	// 撤回后客户端不可见，最新版本回退
+	yanked, err := suite.appService.YankVersion(app.ID, broken.ID, "严重崩溃")
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), yanked.Yanked)
+
+	retrievedApp, _ := suite.appService.GetApplication(app.ID)
+	assert.Equal(suite.T(), "1.0.0", retrievedApp.LatestVersion)
+	latest, _ := NewUpdateService().GetLatestForClient(app.ID, models.ChannelStable, "")
+	assert.Equal(suite.T(), "1.0.0", latest.Version)
+
+	// 撤回的版本号不能重复使用
+	_, err = suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{Version: "1.1.0"})
+	assert.Error(suite.T(), err)
+
+	// 恢复撤回
+	_, err = suite.appService.UnyankVersion(app.ID, broken.ID)
+	assert.NoError(suite.T(), err)
+	retrievedApp, _ = suite.appService.GetApplication(app.ID)
+	assert.Equal(suite.T(), "1.1.0", retrievedApp.LatestVersion)
+
+	// 删除全部版本后可以删除应用
+	versions, _ := suite.appService.GetVersions(app.ID)
+	for _, v := range versions {
+		assert.NoError(suite.T(), suite.appService.DeleteVersion(app.ID, v.ID))
+	}
+	retrievedApp, _ = suite.appService.GetApplication(app.ID)
+	assert.Empty(suite.T(), retrievedApp.LatestVersion)
+
+	// 更新日志条目和多语言更新日志随版本一起删除
+	var entryCount, localizedCount int64
+	config.DB.Model(&models.ChangelogEntry{}).Where("version_id IN ?", []uint{versions[0].ID, versions[1].ID}).Count(&entryCount)
+	config.DB.Model(&models.LocalizedChangelog{}).Where("version_id IN ?", []uint{versions[0].ID, versions[1].ID}).Count(&localizedCount)
+	assert.Zero(suite.T(), entryCount)
+	assert.Zero(suite.T(), localizedCount)
+	assert.NoError(suite.T(), suite.appService.DeleteApplication(app.ID))
+
+	// 删除不存在的版本
+	assert.Error(suite.T(), suite.appService.DeleteVersion(app.ID, 99999))
+}
+
+// TestDraftAndScheduledRelease 测试草稿和定时发布
+func (suite *AppServiceTestSuite) TestDraftAndScheduledRelease() {
+	app, _ := suite.appService.CreateApplication("定时发布应用", "测试定时发布")
+	suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{Version: "1.0.0"})
+
+	// 草稿不对客户端可见，也不更新最新版本
+	draft, err := suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{Version: "1.1.0", PublishStatus: models.PublishStatusDraft})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), models.PublishStatusDraft, draft.PublishStatus)
+
+	retrievedApp, _ := suite.appService.GetApplication(app.ID)
+	assert.Equal(suite.T(), "1.0.0", retrievedApp.LatestVersion)
+
+	// 定时发布时间必须在未来
+	past := time.Now().Add(-time.Hour)
+	_, err = suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{Version: "1.2.0", PublishAt: &past})
+	assert.Error(suite.T(), err)
+
+	// 到期后由调度器发布
+	future := time.Now().Add(time.Hour)
+	_, err = suite.appService.ScheduleVersion(app.ID, draft.ID, future)
+	assert.NoError(suite.T(), err)
+	config.DB.Model(&models.Version{}).Where("id = ?", draft.ID).Update("publish_at", past)
+
+	NewReleaseScheduler(suite.appService, time.Minute).RunOnce()
+
+	retrievedApp, _ = suite.appService.GetApplication(app.ID)
+	assert.Equal(suite.T(), "1.1.0", retrievedApp.LatestVersion)
+
+	var auditCount int64
+	config.DB.Model(&models.AuditLog{}).Where("entity_type = ? AND entity_id = ?", "version", fmt.Sprint(draft.ID)).Count(&auditCount)
+	assert.Equal(suite.T(), int64(1), auditCount)
+
+	// 已发布的版本不能再次发布
+	_, err = suite.appService.PublishVersion(app.ID, draft.ID)
+	assert.Error(suite.T(), err)
+}
+
+// TestVersionArtifacts 测试构建产物
+func (suite *AppServiceTestSuite) TestVersionArtifacts() {
+	app, _ := suite.appService.CreateApplication("构建产物应用", "测试构建产物")
+	suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{Version: "1.0.0"})
+	version, _ := suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{Version: "1.1.0"})
+
+	artifactService := NewArtifactService()
+	checksum := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	_, err := artifactService.CreateArtifact(app.ID, version.ID, &models.CreateArtifactRequest{
+		Platform: "windows-x64", URL: "https://cdn.example.com/app-1.1.0-x64.exe", Size: 1024, SHA256: checksum,
+	})
+	assert.NoError(suite.T(), err)
+	_, err = artifactService.CreateArtifact(app.ID, version.ID, &models.CreateArtifactRequest{
+		Platform: "macos", Arch: "universal", URL: "https://cdn.example.com/app-1.1.0.dmg",
+	})
+	assert.NoError(suite.T(), err)
+
+	// 参数校验
+	_, err = artifactService.CreateArtifact(app.ID, version.ID, &models.CreateArtifactRequest{Platform: "symbian", URL: "https://cdn.example.com/a"})
+	assert.Error(suite.T(), err)
+	_, err = artifactService.CreateArtifact(app.ID, version.ID, &models.CreateArtifactRequest{Platform: "android", URL: "ftp://cdn.example.com/a.apk"})
+	assert.Error(suite.T(), err)
+	_, err = artifactService.CreateArtifact(app.ID, version.ID, &models.CreateArtifactRequest{Platform: "android", URL: "https://cdn.example.com/a.apk", SHA256: "xyz"})
+	assert.Error(suite.T(), err)
+
+	// 按平台匹配
+	artifact, err := artifactService.FindArtifact(version.ID, "windows", "x64")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "app-1.1.0-x64.exe", artifact.FileName)
+	assert.Equal(suite.T(), checksum, artifact.SHA256)
+
+	artifact, _ = artifactService.FindArtifact(version.ID, "macos-arm64", "")
+	assert.Equal(suite.T(), "universal", artifact.Arch)
+
+	artifact, _ = artifactService.FindArtifact(version.ID, "android", "")
+	assert.Nil(suite.T(), artifact)
+
+	// 更新检查返回匹配的产物
+	result, err := NewUpdateService().CheckUpdate(app, &models.UpdateCheckRequest{Current: "1.0.0", Platform: "windows-x64"})
+	assert.NoError(suite.T(), err)
+	assert.NotNil(suite.T(), result.Artifact)
+	assert.Equal(suite.T(), "windows-x64", result.Artifact.Target())
+}
+
+// TestUploadArtifact 测试上传托管构建产物
+func (suite *AppServiceTestSuite) TestUploadArtifact() {
+	fileStorage, err := storage.NewFileSystemStorage(suite.T().TempDir())
+	suite.Require().NoError(err)
+	config.Storage = fileStorage
+
+	app, _ := suite.appService.CreateApplication("上传产物应用", "测试上传构建产物")
+	version, _ := suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{Version: "1.0.0"})
+
+	artifactService := NewArtifactService()
+	content := []byte("installer content")
+	artifact, err := artifactService.UploadArtifact(context.Background(), app.ID, version.ID,
+		&models.UploadArtifactRequest{Platform: "linux-x64", FileName: "app.tar.gz"}, bytes.NewReader(content))
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(len(content)), artifact.Size)
+	assert.Equal(suite.T(), utils.HashAPIKey(string(content)), artifact.SHA256)
+	assert.Equal(suite.T(), models.ArtifactDownloadPath(artifact.ID), artifact.URL)
+
+	// 校验值不匹配时拒绝
+	_, err = artifactService.UploadArtifact(context.Background(), app.ID, version.ID,
+		&models.UploadArtifactRequest{Platform: "linux-x64", FileName: "app.tar.gz", SHA256: strings.Repeat("0", 64)}, bytes.NewReader(content))
+	assert.Error(suite.T(), err)
+
+	downloadable, err := artifactService.GetDownloadableArtifact(app.ID, artifact.ID)
+	assert.NoError(suite.T(), err)
+	file, _, err := config.Storage.Open(context.Background(), downloadable.StorageKey)
+	assert.NoError(suite.T(), err)
+	stored, _ := io.ReadAll(file)
+	file.Close()
+	assert.Equal(suite.T(), content, stored)
+
+	// 其他应用无法下载
+	_, err = artifactService.GetDownloadableArtifact(app.ID+1, artifact.ID)
+	assert.Error(suite.T(), err)
+
+	// 重新上传同名文件时替换原文件，原文件在新记录保存后删除
+	replaced, err := artifactService.UploadArtifact(context.Background(), app.ID, version.ID,
+		&models.UploadArtifactRequest{Platform: "linux-x64", FileName: "app.tar.gz"}, bytes.NewReader([]byte("new installer")))
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), artifact.ID, replaced.ID)
+	assert.NotEqual(suite.T(), downloadable.StorageKey, replaced.StorageKey)
+	_, _, err = config.Storage.Open(context.Background(), downloadable.StorageKey)
+	assert.ErrorIs(suite.T(), err, storage.ErrNotFound)
+	downloadable = replaced
+
+	// 删除产物时同时删除文件
+	assert.NoError(suite.T(), artifactService.DeleteArtifact(app.ID, version.ID, artifact.ID))
+	_, _, err = config.Storage.Open(context.Background(), downloadable.StorageKey)
+	assert.ErrorIs(suite.T(), err, storage.ErrNotFound)
+
+	// 撤回版本后产物不可下载
+	artifact, err = artifactService.UploadArtifact(context.Background(), app.ID, version.ID,
+		&models.UploadArtifactRequest{Platform: "linux-x64", FileName: "app.tar.gz"}, bytes.NewReader(content))
+	assert.NoError(suite.T(), err)
+	_, err = suite.appService.YankVersion(app.ID, version.ID, "安装包损坏")
+	assert.NoError(suite.T(), err)
+	_, err = artifactService.GetDownloadableArtifact(app.ID, artifact.ID)
+	assert.Error(suite.T(), err)
+
+	// 删除版本时同时删除产物记录和文件
+	assert.NoError(suite.T(), suite.appService.DeleteVersion(app.ID, version.ID))
+	artifacts, _ := artifactService.GetArtifacts(app.ID, version.ID)
+	assert.Empty(suite.T(), artifacts)
+	_, _, err = config.Storage.Open(context.Background(), artifact.StorageKey)
+	assert.ErrorIs(suite.T(), err, storage.ErrNotFound)
+}
+
+// TestSigningKeys 测试清单签名与签名密钥轮换
+func (suite *AppServiceTestSuite) TestSigningKeys() {
+	app, _ := suite.appService.CreateApplication("签名应用", "测试清单签名")
+	signingService := NewSigningService()
+
+	payload := map[string]interface{}{"version": "1.0.0", "changelog": "<p>首个版本</p>"}
+	signature, err := signingService.Sign(app.ID, payload)
+	assert.NoError(suite.T(), err)
+
+	keys, err := signingService.GetPublicKeys(app.ID)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), keys, 1)
+	assert.Equal(suite.T(), signature.KeyID, keys[0].KeyID)
+
+	data, _ := utils.CanonicalJSON(payload)
+	assert.True(suite.T(), utils.VerifyEd25519(keys[0].PublicKey, data, signature.Signature))
+
+	// 轮换后宽限期内同时发布新旧公钥，新签名使用新密钥
+	newKey, err := signingService.RotateSigningKey(app.ID, time.Hour)
+	assert.NoError(suite.T(), err)
+	keys, _ = signingService.GetPublicKeys(app.ID)
+	assert.Len(suite.T(), keys, 2)
+
+	signature, _ = signingService.Sign(app.ID, payload)
+	assert.Equal(suite.T(), newKey.KeyID, signature.KeyID)
+
+	// 不保留宽限期时被轮换的公钥立即停止发布，更早的旧公钥不受影响
+	latestKey, err := signingService.RotateSigningKey(app.ID, 0)
+	assert.NoError(suite.T(), err)
+	keys, _ = signingService.GetPublicKeys(app.ID)
+	assert.Len(suite.T(), keys, 2)
+	assert.Equal(suite.T(), latestKey.KeyID, keys[0].KeyID)
+	for _, key := range keys {
+		assert.NotEqual(suite.T(), newKey.KeyID, key.KeyID)
+	}
+
+	// 私钥种子加密存储
+	var stored models.SigningKey
+	config.DB.Where("key_id = ?", latestKey.KeyID).First(&stored)
+	assert.True(suite.T(), utils.IsSealedSecret(stored.PrivateKey))
+}
+
+// TestSigningKeyConcurrentCreate 测试并发的首次签名请求只创建一个签名密钥
+func (suite *AppServiceTestSuite) TestSigningKeyConcurrentCreate() {
+	app, _ := suite.appService.CreateApplication("并发签名应用", "测试并发创建签名密钥")
+	signingService := NewSigningService()
+
+	var wg sync.WaitGroup
+	keyIDs := make([]string, 10)
+	for i := range keyIDs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			signature, err := signingService.Sign(app.ID, map[string]interface{}{"version": "1.0.0"})
+			if assert.NoError(suite.T(), err) {
+				keyIDs[i] = signature.KeyID
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	var count int64
+	config.DB.Model(&models.SigningKey{}).Where("app_id = ? AND status = ?", app.ID, models.SigningKeyStatusActive).Count(&count)
+	assert.Equal(suite.T(), int64(1), count)
+	for _, keyID := range keyIDs {
+		assert.Equal(suite.T(), keyIDs[0], keyID)
+	}
+
+	// 不存在的应用不会创建密钥
+	_, err := signingService.Sign(99999, map[string]interface{}{})
+	assert.ErrorIs(suite.T(), err, ErrAppNotFound)
+}
+
+// TestFeedReleases 测试更新源版本列表
+func (suite *AppServiceTestSuite) TestFeedReleases() {
+	app, _ := suite.appService.CreateApplication("更新源应用", "测试更新源")
+	stable, _ := suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{Version: "1.0.0"})
+	suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{Version: "1.1.0-beta.1", Channel: models.ChannelBeta})
+	suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{Version: "1.1.0", PublishStatus: models.PublishStatusDraft})
+
+	artifactService := NewArtifactService()
+	_, err := artifactService.CreateArtifact(app.ID, stable.ID, &models.CreateArtifactRequest{
+		Platform: "macos", URL: "https://cdn.example.com/app-1.0.0.dmg", EdSignature: "invalid",
+	})
+	assert.Error(suite.T(), err)
+	_, err = artifactService.CreateArtifact(app.ID, stable.ID, &models.CreateArtifactRequest{
+		Platform: "macos", URL: "https://cdn.example.com/app-1.0.0.dmg", MinimumSystemVersion: "10.15",
+	})
+	assert.NoError(suite.T(), err)
+
+	feedService := NewFeedService()
+	releases, err := feedService.GetReleases(app.ID, models.ChannelBeta, "", "macos", "", 0)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), releases, 2) // 草稿不出现在更新源中
+	assert.Equal(suite.T(), "1.1.0-beta.1", releases[0].Version)
+	assert.Nil(suite.T(), releases[0].Artifact)
+	assert.Equal(suite.T(), "10.15", releases[1].Artifact.MinimumSystemVersion)
+	assert.Len(suite.T(), releases[1].Artifacts, 1)
+
+	releases, _ = feedService.GetReleases(app.ID, models.ChannelStable, "", "macos", "", 0)
+	assert.Len(suite.T(), releases, 1)
+
+	// 公开订阅源默认关闭
+	_, _, err = feedService.GetPublicReleases(app.ID)
+	assert.Error(suite.T(), err)
+
+	enabled := true
+	_, err = suite.appService.UpdateApplication(app.ID, &models.UpdateApplicationRequest{PublicFeedEnabled: &enabled})
+	assert.NoError(suite.T(), err)
+	_, releases, err = feedService.GetPublicReleases(app.ID)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), releases, 1)
+}
+
+// TestCompareVersions 测试版本对比
+func (suite *AppServiceTestSuite) TestCompareVersions() {
+	app, _ := suite.appService.CreateApplication("版本对比应用", "测试版本对比")
+	suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{Version: "1.2.0", ChangelogMD: "基础版本"})
+	suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{Version: "1.3.0", ChangelogMD: "移除旧接口", Breaking: true})
+	suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{Version: "1.4.0-beta.1", ChangelogMD: "测试版", Channel: models.ChannelBeta})
+	suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{Version: "1.5.0", ChangelogMD: "修复漏洞", Security: true})
+	suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{Version: "1.7.0", ChangelogMD: "超出范围"})
+
+	updateService := NewUpdateService()
+	comparison, err := updateService.CompareVersions(app.ID, &models.CompareVersionsRequest{From: "1.2.0", To: "1.6.3"})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), comparison.Versions, 2)
+	assert.Equal(suite.T(), "1.3.0", comparison.Versions[0].Version)
+	assert.Equal(suite.T(), "1.5.0", comparison.Versions[1].Version)
+	assert.True(suite.T(), comparison.Breaking)
+	assert.True(suite.T(), comparison.Security)
+	assert.Equal(suite.T(), []string{"1.3.0"}, comparison.BreakingVersions)
+	assert.Equal(suite.T(), []string{"1.5.0"}, comparison.SecurityVersions)
+	assert.Equal(suite.T(), "## 1.3.0\n\n移除旧接口\n\n## 1.5.0\n\n修复漏洞", comparison.ChangelogMD)
+	assert.Contains(suite.T(), comparison.ChangelogHTML, "<h2>1.5.0</h2>")
+
+	// beta 渠道包含测试版
+	comparison, _ = updateService.CompareVersions(app.ID, &models.CompareVersionsRequest{From: "1.2.0", To: "1.6.3", Channel: models.ChannelBeta})
+	assert.Len(suite.T(), comparison.Versions, 3)
+
+	_, err = updateService.CompareVersions(app.ID, &models.CompareVersionsRequest{From: "1.6.3", To: "1.2.0"})
+	assert.Error(suite.T(), err)
+}
+
+// TestChangelogEntries 测试结构化更新日志条目
+func (suite *AppServiceTestSuite) TestChangelogEntries() {
+	app, _ := suite.appService.CreateApplication("结构化日志应用", "测试结构化更新日志")
+	suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{Version: "1.0.0", ChangelogMD: "初始版本"})
+	version, err := suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{
+		Version: "1.1.0",
+		Entries: []models.ChangelogEntryRequest{
+			{Category: models.ChangelogFixed, Description: "修复登录失败", Issues: []string{"#12"}},
+			{Category: models.ChangelogAdded, Description: "支持深色模式"},
+			{Category: models.ChangelogSecurity, Description: "升级加密库", Issues: []string{"34", "https://example.com/advisory/1"}},
+		},
+	})
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), version.Security)
+	assert.Equal(suite.T(), "### Added\n\n- 支持深色模式\n\n### Fixed\n\n- 修复登录失败 (#12)\n\n"+
+		"### Security\n\n- 升级加密库 (#34, https://example.com/advisory/1)", version.ChangelogMD)
+
+	// 无效分类
+	_, err = suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{
+		Version: "1.2.0",
+		Entries: []models.ChangelogEntryRequest{{Category: "misc", Description: "其他"}},
+	})
+	assert.Error(suite.T(), err)
+
+	// 按分类过滤更新检查结果
+	result, err := NewUpdateService().CheckUpdate(app, &models.UpdateCheckRequest{Current: "1.0.0", Category: models.ChangelogSecurity, Format: "markdown"})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "## 1.1.0\n\n### Security\n\n- 升级加密库 (#34, https://example.com/advisory/1)", result.Changelog)
+
+	// 替换条目后重新生成更新日志
+	entries := []models.ChangelogEntryRequest{{Category: models.ChangelogChanged, Description: "调整默认设置"}}
+	version, err = suite.appService.UpdateVersion(app.ID, version.ID, &models.UpdateVersionRequest{Entries: &entries})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "### Changed\n\n- 调整默认设置", version.ChangelogMD)
+	stored, _ := NewChangelogService().GetEntries(version.ID)
+	assert.Len(suite.T(), stored, 1)
+
+	// 同时提供 Markdown 和条目时由条目生成
+	markdown := "手写的更新日志"
+	version, err = suite.appService.UpdateVersion(app.ID, version.ID, &models.UpdateVersionRequest{ChangelogMD: &markdown, Entries: &entries})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "### Changed\n\n- 调整默认设置", version.ChangelogMD)
+
+	// 只提供 Markdown 时清除结构化条目
+	version, err = suite.appService.UpdateVersion(app.ID, version.ID, &models.UpdateVersionRequest{ChangelogMD: &markdown})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), markdown, version.ChangelogMD)
+	assert.Empty(suite.T(), version.Entries)
+	stored, _ = NewChangelogService().GetEntries(version.ID)
+	assert.Empty(suite.T(), stored)
+
+	// 只修改其他字段时保留更新日志
+	forceUpdate := true
+	version, err = suite.appService.UpdateVersion(app.ID, version.ID, &models.UpdateVersionRequest{ForceUpdate: &forceUpdate})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), markdown, version.ChangelogMD)
+}
+
+// TestLocalizedChangelogs 测试多语言更新日志及语言回退
+func (suite *AppServiceTestSuite) TestLocalizedChangelogs() {
+	app, _ := suite.appService.CreateApplication("多语言日志应用", "测试多语言更新日志")
+	version, err := suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{
+		Version:     "1.0.0",
+		ChangelogMD: "默认更新日志",
+		Changelogs: map[string]string{
+			"en":      "English changelog",
+			"zh_Hant": "繁體更新日誌",
+			"zh-Hans": "简体更新日志",
+		},
+	})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), version.Changelogs, 3)
+
+	changelogService := NewChangelogService()
+	resolve := func(lang, acceptLanguage string) string {
+		chain := utils.LocaleFallbackChain(utils.PreferredLocales(lang, acceptLanguage))
+		localized, err := changelogService.ResolveChangelog(version.ID, chain)
+		assert.NoError(suite.T(), err)
+		if localized == nil {
+			return ""
+		}
+		return localized.Locale
+	}
+	assert.Equal(suite.T(), "en", resolve("", "en-US,en;q=0.9"))
+	assert.Equal(suite.T(), "zh-Hant", resolve("", "zh-TW"))
+	assert.Equal(suite.T(), "zh-Hans", resolve("", "zh-CN"))
+	assert.Equal(suite.T(), "en", resolve("", "ja,en;q=0.5"))
+	assert.Equal(suite.T(), "en", resolve("en-GB", "zh-TW"))
+	assert.Equal(suite.T(), "", resolve("", "ja"))
+
+	// 无效的语言标签
+	_, err = suite.appService.UpdateVersion(app.ID, version.ID, &models.UpdateVersionRequest{
+		Changelogs: map[string]string{"不是语言": "内容"},
+	})
+	assert.Error(suite.T(), err)
+
+	// 整体替换多语言更新日志
+	version, err = suite.appService.UpdateVersion(app.ID, version.ID, &models.UpdateVersionRequest{
+		Changelogs: map[string]string{"ja": "日本語の更新履歴"},
+	})
+	assert.NoError(suite.T(), err)
+	changelogs, _ := changelogService.GetLocalizedChangelogs(version.ID)
+	assert.Len(suite.T(), changelogs, 1)
+	assert.Equal(suite.T(), "ja", changelogs[0].Locale)
+	assert.Contains(suite.T(), changelogs[0].ChangelogHTML, "日本語の更新履歴")
+	assert.Equal(suite.T(), "", resolve("", "en"))
+}
+
+// TestMemberLevels 测试按应用管理会员等级
+func (suite *AppServiceTestSuite) TestMemberLevels() {
+	memberService := NewMemberService()
+	appA, _ := suite.appService.CreateApplication("会员应用A", "测试会员等级")
+	appB, _ := suite.appService.CreateApplication("会员应用B", "测试会员等级")
+
+	levels, err := memberService.UpdateMemberLevels(appA.ID, []models.MemberLevelRequest{
+		{Name: "高级会员", Level: 2, Permissions: json.RawMessage(`{"features":["basic","advanced"]}`)},
+		{Name: "普通会员", Level: 1},
+	})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), levels, 2)
+	assert.Equal(suite.T(), "普通会员", levels[0].Name)
+	assert.Equal(suite.T(), "{}", levels[0].Permissions)
+	assert.Equal(suite.T(), appA.ID, levels[1].AppID)
+
+	_, err = memberService.UpdateMemberLevels(appB.ID, []models.MemberLevelRequest{
+		{Name: "企业会员", Level: 1, Permissions: json.RawMessage(`"{\"features\":[\"enterprise\"]}"`)},
+	})
+	assert.NoError(suite.T(), err)
+
+	// 更新一个应用的会员等级不影响其他应用
+	levels, err = memberService.UpdateMemberLevels(appA.ID, []models.MemberLevelRequest{
+		{Name: "免费会员", Level: 1},
+	})
+	assert.NoError(suite.T(), err)
+	levels, _ = memberService.GetMemberLevels(appA.ID)
+	assert.Len(suite.T(), levels, 1)
+	assert.Equal(suite.T(), "免费会员", levels[0].Name)
+	levels, _ = memberService.GetMemberLevels(appB.ID)
+	assert.Len(suite.T(), levels, 1)
+	assert.Equal(suite.T(), "企业会员", levels[0].Name)
+
+	// 无效请求不会修改已有数据
+	_, err = memberService.UpdateMemberLevels(appA.ID, []models.MemberLevelRequest{
+		{Name: "会员", Level: 1},
+		{Name: "会员", Level: 2},
+	})
+	assert.Error(suite.T(), err)
+	_, err = memberService.UpdateMemberLevels(appA.ID, []models.MemberLevelRequest{
+		{Name: "会员", Level: 1, Permissions: json.RawMessage("{invalid")},
+	})
+	assert.Error(suite.T(), err)
+	_, err = memberService.UpdateMemberLevels(999999, []models.MemberLevelRequest{{Name: "会员", Level: 1}})
+	assert.Error(suite.T(), err)
+	levels, _ = memberService.GetMemberLevels(appA.ID)
+	assert.Equal(suite.T(), "免费会员", levels[0].Name)
+}
+
+// TestPermissionSchema 测试按应用Schema校验会员权限配置
+func (suite *AppServiceTestSuite) TestPermissionSchema() {
+	memberService := NewMemberService()
+	schemaService := NewPermissionSchemaService()
+	app, _ := suite.appService.CreateApplication("Schema应用", "测试权限配置Schema")
+
+	_, err := memberService.UpdateMemberLevels(app.ID, []models.MemberLevelRequest{
+		{Name: "普通会员", Level: 1, Permissions: json.RawMessage(`{"features":["basic"],"limits":{"api_calls":"1000"}}`)},
+	})
+	assert.NoError(suite.T(), err)
+
+	schemaDoc := json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"features": {"type": "array", "items": {"type": "string"}},
+			"limits": {"type": "object", "properties": {"api_calls": {"type": "integer"}}}
+		},
+		"required": ["features"]
+	}`)
+
+	// 已有会员等级不符合新Schema时拒绝注册
+	_, err = schemaService.SetSchema(app.ID, schemaDoc)
+	var validationErr *PermissionValidationError
+	assert.ErrorAs(suite.T(), err, &validationErr)
+	assert.Equal(suite.T(), "levels[0].permissions.limits.api_calls", validationErr.Errors[0].Field)
+
+	_, err = memberService.UpdateMemberLevels(app.ID, []models.MemberLevelRequest{
+		{Name: "普通会员", Level: 1, Permissions: json.RawMessage(`{"features":["basic"],"limits":{"api_calls":1000}}`)},
+	})
+	assert.NoError(suite.T(), err)
+	schema, err := schemaService.SetSchema(app.ID, schemaDoc)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), app.ID, schema.AppID)
+
+	// 更新会员等级时按Schema校验，错误指向具体字段
+	_, err = memberService.UpdateMemberLevels(app.ID, []models.MemberLevelRequest{
+		{Name: "普通会员", Level: 1, Permissions: json.RawMessage(`{"features":["basic"]}`)},
+		{Name: "高级会员", Level: 2, Permissions: json.RawMessage(`{"features":["basic",2]}`)},
+		{Name: "企业会员", Level: 3, Standalone: true},
+	})
+	assert.ErrorAs(suite.T(), err, &validationErr)
+	fields := make([]string, len(validationErr.Errors))
+	for i, e := range validationErr.Errors {
+		fields[i] = e.Field
+	}
+	assert.Equal(suite.T(), []string{"levels[1].permissions.features[1]", "levels[2].permissions"}, fields)
+
+	// 无效的Schema
+	_, err = schemaService.SetSchema(app.ID, json.RawMessage(`{"type": 1}`))
+	assert.Error(suite.T(), err)
+
+	// 删除Schema后只校验JSON格式
+	assert.NoError(suite.T(), schemaService.DeleteSchema(app.ID))
+	_, err = schemaService.GetSchema(app.ID)
+	assert.Error(suite.T(), err)
+	_, err = memberService.UpdateMemberLevels(app.ID, []models.MemberLevelRequest{{Name: "企业会员", Level: 3}})
+	assert.NoError(suite.T(), err)
+}
+
+// TestEffectivePermissions 测试会员等级继承及权限合并
+func (suite *AppServiceTestSuite) TestEffectivePermissions() {
+	memberService := NewMemberService()
+	app, _ := suite.appService.CreateApplication("继承应用", "测试会员等级继承")
+
+	basic := 1
+	_, err := memberService.UpdateMemberLevels(app.ID, []models.MemberLevelRequest{
+		{Name: "普通会员", Level: 1, Permissions: json.RawMessage(`{"features":["basic"],"limits":{"api_calls":1000,"storage":"1GB"},"ads":false}`)},
+		{Name: "高级会员", Level: 2, Permissions: json.RawMessage(`{"features":["advanced"],"limits":{"api_calls":5000},"ads":true}`)},
+		{Name: "企业会员", Level: 3, Permissions: json.RawMessage(`{"features":["enterprise"]}`)},
+		{Name: "教育会员", Level: 4, ParentLevel: &basic, Permissions: json.RawMessage(`{"features":["classroom"]}`)},
+		{Name: "试用会员", Level: 5, Standalone: true, Permissions: json.RawMessage(`{"features":["trial"]}`)},
+	})
+	assert.NoError(suite.T(), err)
+
+	levels, err := memberService.GetEffectiveMemberLevels(app.ID)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), levels, 5)
+
+	assert.Empty(suite.T(), levels[0].Inherits)
+	assert.Equal(suite.T(), []int{2, 1}, levels[2].Inherits)
+	assert.JSONEq(suite.T(), `{"features":["basic","advanced","enterprise"],"limits":{"api_calls":5000,"storage":"1GB"},"ads":true}`, string(levels[2].Permissions))
+	assert.Equal(suite.T(), []int{1}, levels[3].Inherits)
+	assert.JSONEq(suite.T(), `{"features":["basic","classroom"],"limits":{"api_calls":1000,"storage":"1GB"},"ads":false}`, string(levels[3].Permissions))
+	assert.Empty(suite.T(), levels[4].Inherits)
+	assert.JSONEq(suite.T(), `{"features":["trial"]}`, string(levels[4].Permissions))
+
+	// 继承的等级不存在或存在循环
+	missing, two, three := 9, 2, 3
+	_, err = memberService.UpdateMemberLevels(app.ID, []models.MemberLevelRequest{
+		{Name: "普通会员", Level: 1, ParentLevel: &missing},
+	})
+	assert.Error(suite.T(), err)
+	_, err = memberService.UpdateMemberLevels(app.ID, []models.MemberLevelRequest{
+		{Name: "普通会员", Level: 1},
+		{Name: "高级会员", Level: 2, ParentLevel: &three},
+		{Name: "企业会员", Level: 3, ParentLevel: &two},
+	})
+	assert.Error(suite.T(), err)
+}
+
+// TestAppMembers 测试终端用户会员订阅
+func (suite *AppServiceTestSuite) TestAppMembers() {
+	memberService := NewMemberService()
+	appMemberService := NewAppMemberService()
+	app, _ := suite.appService.CreateApplication("订阅应用", "测试会员订阅")
+
+	levels, err := memberService.UpdateMemberLevels(app.ID, []models.MemberLevelRequest{
+		{Name: "普通会员", Level: 1, Permissions: json.RawMessage(`{"features":["basic"]}`)},
+		{Name: "高级会员", Level: 2, Permissions: json.RawMessage(`{"features":["advanced"]}`)},
+	})
+	assert.NoError(suite.T(), err)
+	premiumID := levels[1].ID
+
+	expiresAt := time.Now().Add(24 * time.Hour)
+	member, err := appMemberService.CreateMember(app.ID, &models.CreateAppMemberRequest{
+		ExternalUserID: "user-1",
+		MemberLevelID:  premiumID,
+		ExpiresAt:      &expiresAt,
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), models.AppMemberStatusActive, member.Status)
+	assert.Equal(suite.T(), "高级会员", member.MemberLevel.Name)
+
+	// 同一用户只能有一条订阅，等级必须属于该应用
+	_, err = appMemberService.CreateMember(app.ID, &models.CreateAppMemberRequest{ExternalUserID: "user-1", MemberLevelID: premiumID})
+	assert.Error(suite.T(), err)
+	_, err = appMemberService.CreateMember(app.ID, &models.CreateAppMemberRequest{ExternalUserID: "user-2", MemberLevelID: 999999})
+	assert.Error(suite.T(), err)
+
+	// 当前会员信息附带合并继承后的权限
+	membership, err := appMemberService.GetMembership(app.ID, "user-1")
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), membership.Active)
+	assert.Equal(suite.T(), 2, membership.Level.Level)
+	assert.JSONEq(suite.T(), `{"features":["basic","advanced"]}`, string(membership.Level.Permissions))
+
+	_, err = appMemberService.GetMembership(app.ID, "unknown")
+	assert.ErrorIs(suite.T(), err, ErrMemberNotFound)
+
+	// 修改会员等级时保留原有等级ID，仍被使用的等级不能删除
+	levels, err = memberService.UpdateMemberLevels(app.ID, []models.MemberLevelRequest{
+		{Name: "免费会员", Level: 1},
+		{Name: "专业会员", Level: 2},
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), premiumID, levels[1].ID)
+	_, err = memberService.UpdateMemberLevels(app.ID, []models.MemberLevelRequest{{Name: "免费会员", Level: 1}})
+	assert.Error(suite.T(), err)
+
+	// 暂停和过期的订阅不再生效
+	suspended := models.AppMemberStatusSuspended
+	member, err = appMemberService.UpdateMember(app.ID, member.ID, &models.UpdateAppMemberRequest{Status: &suspended})
+	assert.NoError(suite.T(), err)
+	membership, _ = appMemberService.GetMembership(app.ID, "user-1")
+	assert.False(suite.T(), membership.Active)
+	assert.Nil(suite.T(), membership.Level)
+
+	active := models.AppMemberStatusActive
+	startsAt := time.Now().Add(-48 * time.Hour)
+	expired := time.Now().Add(-time.Hour)
+	_, err = appMemberService.UpdateMember(app.ID, member.ID, &models.UpdateAppMemberRequest{Status: &active, StartsAt: &startsAt, ExpiresAt: &expired})
+	assert.NoError(suite.T(), err)
+	membership, _ = appMemberService.GetMembership(app.ID, "user-1")
+	assert.Equal(suite.T(), models.AppMemberStatusExpired, membership.Status)
+
+	member, err = appMemberService.UpdateMember(app.ID, member.ID, &models.UpdateAppMemberRequest{NeverExpires: true})
+	assert.NoError(suite.T(), err)
+	assert.Nil(suite.T(), member.ExpiresAt)
+
+	members, total, err := appMemberService.GetMembers(app.ID, models.AppMemberStatusActive, 10, 0)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), total)
+	assert.Len(suite.T(), members, 1)
+
+	assert.NoError(suite.T(), appMemberService.DeleteMember(app.ID, member.ID))
+	assert.Error(suite.T(), appMemberService.DeleteMember(app.ID, member.ID))
+}
+
+// TestCheckEntitlements 测试终端用户权益检查
+func (suite *AppServiceTestSuite) TestCheckEntitlements() {
+	memberService := NewMemberService()
+	appMemberService := NewAppMemberService()
+	app, _ := suite.appService.CreateApplication("权益应用", "测试权益检查")
+
+	levels, _ := memberService.UpdateMemberLevels(app.ID, []models.MemberLevelRequest{
+		{Name: "普通会员", Level: 1, Permissions: json.RawMessage(`{"features":["basic"],"restrictions":["export"],"limits":{"api_calls":1000,"exports":0}}`)},
+		{Name: "高级会员", Level: 2, Permissions: json.RawMessage(`{"features":["advanced","export"],"limits":{"api_calls":5000}}`)},
+	})
+	_, err := appMemberService.CreateMember(app.ID, &models.CreateAppMemberRequest{ExternalUserID: "user-1", MemberLevelID: levels[1].ID})
+	assert.NoError(suite.T(), err)
+	_, err = appMemberService.CreateMember(app.ID, &models.CreateAppMemberRequest{ExternalUserID: "user-2", MemberLevelID: levels[0].ID})
+	assert.NoError(suite.T(), err)
+
+	// 高级会员开放的功能解除普通会员的同名限制
+	check, err := appMemberService.CheckEntitlements(app.ID, &models.EntitlementCheckRequest{
+		ExternalUserID: "user-2",
+		Features:       []string{"basic", "export"},
+	})
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), check.Results[0].Allowed)
+	assert.False(suite.T(), check.Results[1].Allowed)
+
+	check, err = appMemberService.CheckEntitlements(app.ID, &models.EntitlementCheckRequest{
+		ExternalUserID: "user-1",
+		Features:       []string{"basic", "advanced", "enterprise", "limits.api_calls", "/limits/exports", "export"},
+	})
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), check.Active)
+	assert.Equal(suite.T(), "高级会员", check.Level.Name)
+	allowed := make([]bool, len(check.Results))
+	for i, r := range check.Results {
+		allowed[i] = r.Allowed
+	}
+	assert.Equal(suite.T(), []bool{true, true, false, true, false, true}, allowed)
+	assert.Equal(suite.T(), json.Number("5000"), check.Results[3].Value)
+
+	// 没有会员订阅的用户全部拒绝
+	check, err = appMemberService.CheckEntitlements(app.ID, &models.EntitlementCheckRequest{
+		ExternalUserID: "unknown",
+		Features:       []string{"basic"},
+	})
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), check.Active)
+	assert.Nil(suite.T(), check.Level)
+	assert.False(suite.T(), check.Results[0].Allowed)
+
+	_, err = appMemberService.CheckEntitlements(app.ID, &models.EntitlementCheckRequest{ExternalUserID: "user-1"})
+	assert.Error(suite.T(), err)
 }
 
 // TestGetVersions 测试获取版本列表
 func (suite *AppServiceTestSuite) TestGetVersions() {
 	// 创建测试应用和版本
 	app, _ := suite.appService.CreateApplication("版本列表测试", "测试版本列表")
-	suite.appService.CreateVersion(app.ID, "1.0.0", "版本1")
-	suite.appService.CreateVersion(app.ID, "1.1.0", "版本2")
+	suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{Version: "1.0.0", ChangelogMD: "版本1"})
+	suite.appService.CreateVersion(app.ID, &models.CreateVersionRequest{Version: "1.1.0", ChangelogMD: "版本2"})
 
 	// 获取版本列表
 	versions, err := suite.appService.GetVersions(app.ID)
@@ -140,6 +1156,47 @@ func (suite *AppServiceTestSuite) TestGetVersions() {
 	assert.Equal(suite.T(), "1.0.0", versions[1].Version)
 }
 
+// TestAPIKeys 测试创建和吊销命名API密钥
+func (suite *AppServiceTestSuite) TestAPIKeys() {
+	app, _ := suite.appService.CreateApplication("密钥应用", "测试命名API密钥")
+	apiKeyService := NewAPIKeyService()
+
+	// 不支持的权限范围
+	_, err := apiKeyService.CreateAPIKey(app.ID, &models.CreateAPIKeyRequest{Name: "未知权限", Scopes: []string{"admin:write"}})
+	assert.Error(suite.T(), err)
+
+	// 已过去的过期时间
+	past := time.Now().Add(-time.Hour)
+	_, err = apiKeyService.CreateAPIKey(app.ID, &models.CreateAPIKeyRequest{Name: "已过期", Scopes: []string{models.ScopeVersionRead}, ExpiresAt: &past})
+	assert.Error(suite.T(), err)
+
+	first, err := apiKeyService.CreateAPIKey(app.ID, &models.CreateAPIKeyRequest{Name: "版本查询", Scopes: []string{models.ScopeVersionRead}})
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), first.Key)
+	second, err := apiKeyService.CreateAPIKey(app.ID, &models.CreateAPIKeyRequest{Name: "会员查询", Scopes: []string{models.ScopeMemberLevelsRead}})
+	assert.NoError(suite.T(), err)
+
+	// 吊销只影响指定的密钥
+	revoked, err := apiKeyService.RevokeAPIKey(app.ID, first.ID)
+	assert.NoError(suite.T(), err)
+	assert.NotNil(suite.T(), revoked.RevokedAt)
+
+	keys, err := apiKeyService.GetAPIKeys(app.ID)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), keys, 2)
+	for _, key := range keys {
+		if key.ID == second.ID {
+			assert.Nil(suite.T(), key.RevokedAt)
+		} else {
+			assert.NotNil(suite.T(), key.RevokedAt)
+		}
+	}
+
+	// 其他应用无法吊销
+	_, err = apiKeyService.RevokeAPIKey(app.ID+1, second.ID)
+	assert.Error(suite.T(), err)
+}
+
 // 运行测试套件
 func TestAppServiceTestSuite(t *testing.T) {
 	suite.Run(t, new(AppServiceTestSuite))
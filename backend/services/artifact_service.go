@@ -0,0 +1,293 @@
+package services
+
+import (
+	"app_management/config"
+	"app_management/models"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+var (
+	sha256Regex        = regexp.MustCompile(`^[0-9a-f]{64}$`)
+	sha1Regex          = regexp.MustCompile(`^[0-9a-f]{40}$`)
+	systemVersionRegex = regexp.MustCompile(`^[0-9]+(\.[0-9]+){0,3}$`)
+)
+
+// ArtifactService 构建产物服务
+type ArtifactService struct {
+	cacheService *CacheService
+}
+
+// NewArtifactService 创建构建产物服务实例
+func NewArtifactService() *ArtifactService {
+	return &ArtifactService{
+		cacheService: NewCacheService(),
+	}
+}
+
+// GetArtifacts 获取版本的构建产物列表
+func (s *ArtifactService) GetArtifacts(appID, versionID uint) ([]models.VersionArtifact, error) {
+	var artifacts []models.VersionArtifact
+	result := config.DB.Where("app_id = ? AND version_id = ?", appID, versionID).
+		Order("platform ASC, arch ASC").Find(&artifacts)
+	return artifacts, result.Error
+}
+
+// CreateArtifact 为版本添加构建产物，同一平台和架构已存在时覆盖
+func (s *ArtifactService) CreateArtifact(appID, versionID uint, req *models.CreateArtifactRequest) (*models.VersionArtifact, error) {
+	platform, arch, err := parseArtifactTarget(req.Platform, req.Arch)
+	if err != nil {
+		return nil, err
+	}
+
+	downloadURL, err := url.Parse(req.URL)
+	if err != nil || (downloadURL.Scheme != "http" && downloadURL.Scheme != "https") || downloadURL.Host == "" {
+		return nil, errors.New("下载地址必须是有效的HTTP(S)地址")
+	}
+	if len(req.URL) > 500 {
+		return nil, errors.New("下载地址不能超过500个字符")
+	}
+
+	checksum := strings.ToLower(req.SHA256)
+	if checksum != "" && !sha256Regex.MatchString(checksum) {
+		return nil, errors.New("SHA-256 校验值格式不正确")
+	}
+	checksumSHA1 := strings.ToLower(req.SHA1)
+	if checksumSHA1 != "" && !sha1Regex.MatchString(checksumSHA1) {
+		return nil, errors.New("SHA-1 校验值格式不正确")
+	}
+	if req.Size < 0 {
+		return nil, errors.New("文件大小不能为负数")
+	}
+	if err := validateArtifactMeta(req.EdSignature, req.MinimumSystemVersion); err != nil {
+		return nil, err
+	}
+
+	fileName := req.FileName
+	if fileName == "" {
+		fileName = path.Base(downloadURL.Path)
+	}
+
+	// 检查版本是否存在
+	if err := s.checkVersion(appID, versionID); err != nil {
+		return nil, err
+	}
+
+	return s.saveArtifact(&models.VersionArtifact{
+		AppID:     appID,
+		VersionID: versionID,
+		Platform:  platform,
+		Arch:      arch,
+		FileName:  fileName,
+		URL:       req.URL,
+		Size:      req.Size,
+		SHA256:    checksum,
+		SHA1:      checksumSHA1,
+
+		EdSignature:          req.EdSignature,
+		MinimumSystemVersion: req.MinimumSystemVersion,
+		UpdaterSignature:     req.UpdaterSignature,
+	})
+}
+
+// UploadArtifact 上传构建产物文件到存储，上传过程中计算大小和SHA-256，同一平台和架构已存在时覆盖
+func (s *ArtifactService) UploadArtifact(ctx context.Context, appID, versionID uint, req *models.UploadArtifactRequest, r io.Reader) (*models.VersionArtifact, error) {
+	platform, arch, err := parseArtifactTarget(req.Platform, req.Arch)
+	if err != nil {
+		return nil, err
+	}
+
+	fileName := path.Base(strings.ReplaceAll(req.FileName, "\\", "/"))
+	if fileName == "" || fileName == "." || fileName == "/" {
+		return nil, errors.New("文件名不能为空")
+	}
+	if len(fileName) > 255 {
+		return nil, errors.New("文件名不能超过255个字符")
+	}
+
+	expected := strings.ToLower(req.SHA256)
+	if expected != "" && !sha256Regex.MatchString(expected) {
+		return nil, errors.New("SHA-256 校验值格式不正确")
+	}
+	if err := validateArtifactMeta(req.EdSignature, req.MinimumSystemVersion); err != nil {
+		return nil, err
+	}
+
+	// 检查版本是否存在
+	if err := s.checkVersion(appID, versionID); err != nil {
+		return nil, err
+	}
+
+	artifact := &models.VersionArtifact{
+		AppID:     appID,
+		VersionID: versionID,
+		Platform:  platform,
+		Arch:      arch,
+		FileName:  fileName,
+
+		EdSignature:          req.EdSignature,
+		MinimumSystemVersion: req.MinimumSystemVersion,
+		UpdaterSignature:     req.UpdaterSignature,
+	}
+
+	// 每次上传使用唯一的存储路径，校验通过并保存记录后才删除被替换的文件，
+	// 校验失败或保存失败时只删除本次上传的文件，不影响正在使用的构建产物
+	token := make([]byte, 8)
+	if _, err := rand.Read(token); err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("apps/%d/versions/%d/%s/%s/%s", appID, versionID, artifact.Target(), hex.EncodeToString(token), fileName)
+
+	object, err := config.Storage.Put(ctx, key, r)
+	if err != nil {
+		return nil, err
+	}
+	if expected != "" && expected != object.SHA256 {
+		s.deleteObject(object.Key)
+		return nil, errors.New("上传文件的 SHA-256 校验值不匹配")
+	}
+
+	artifact.Size = object.Size
+	artifact.SHA256 = object.SHA256
+	artifact.SHA1 = object.SHA1
+	artifact.StorageKey = object.Key
+
+	saved, err := s.saveArtifact(artifact)
+	if err != nil {
+		s.deleteObject(object.Key)
+		return nil, err
+	}
+	return saved, nil
+}
+
+// GetDownloadableArtifact 获取可供外部下载的构建产物，所属版本必须已发布且未撤回
+func (s *ArtifactService) GetDownloadableArtifact(appID, artifactID uint) (*models.VersionArtifact, error) {
+	var artifact models.VersionArtifact
+	err := config.DB.Joins("JOIN versions ON versions.id = version_artifacts.version_id AND versions.deleted_at IS NULL").
+		Where("version_artifacts.id = ? AND version_artifacts.app_id = ? AND versions.publish_status = ? AND versions.yanked = ?",
+			artifactID, appID, models.PublishStatusPublished, false).
+		First(&artifact).Error
+	if err != nil {
+		return nil, errors.New("构建产物不存在")
+	}
+	return &artifact, nil
+}
+
+// checkVersion 检查版本是否属于应用
+func (s *ArtifactService) checkVersion(appID, versionID uint) error {
+	var version models.Version
+	if err := config.DB.Where("id = ? AND app_id = ?", versionID, appID).First(&version).Error; err != nil {
+		return errors.New("版本不存在")
+	}
+	return nil
+}
+
+// saveArtifact 按平台和架构新增或覆盖构建产物，被替换的托管文件会从存储中删除
+func (s *ArtifactService) saveArtifact(artifact *models.VersionArtifact) (*models.VersionArtifact, error) {
+	var existing models.VersionArtifact
+	config.DB.Where("version_id = ? AND platform = ? AND arch = ?", artifact.VersionID, artifact.Platform, artifact.Arch).First(&existing)
+	artifact.ID = existing.ID
+	artifact.CreatedAt = existing.CreatedAt
+
+	if err := config.DB.Save(artifact).Error; err != nil {
+		return nil, err
+	}
+
+	// 托管文件通过下载接口访问
+	if artifact.IsHosted() {
+		artifact.URL = models.ArtifactDownloadPath(artifact.ID)
+		if err := config.DB.Model(artifact).Update("url", artifact.URL).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	if existing.StorageKey != "" && existing.StorageKey != artifact.StorageKey {
+		s.deleteObject(existing.StorageKey)
+	}
+
+	// 清除版本缓存
+	s.cacheService.ClearVersionCache()
+
+	return artifact, nil
+}
+
+// deleteObject 删除存储中的文件，失败时只记录日志
+func (s *ArtifactService) deleteObject(key string) {
+	if config.Storage == nil {
+		return
+	}
+	if err := config.Storage.Delete(context.Background(), key); err != nil {
+		log.Printf("删除构建产物文件失败: %s, %v", key, err)
+	}
+}
+
+// validateArtifactMeta 校验产物的 Ed25519 签名和最低系统版本
+func validateArtifactMeta(edSignature, minimumSystemVersion string) error {
+	if edSignature != "" {
+		signature, err := base64.StdEncoding.DecodeString(edSignature)
+		if err != nil || len(signature) != ed25519.SignatureSize {
+			return errors.New("Ed25519 签名格式不正确")
+		}
+	}
+	if minimumSystemVersion != "" && !systemVersionRegex.MatchString(minimumSystemVersion) {
+		return errors.New("最低系统版本格式不正确")
+	}
+	return nil
+}
+
+// parseArtifactTarget 解析并校验平台和架构
+func parseArtifactTarget(platform, arch string) (string, string, error) {
+	platform, arch = models.ParseArtifactTarget(platform, arch)
+	if !models.IsValidArtifactPlatform(platform) {
+		return "", "", errors.New("不支持的平台: " + platform)
+	}
+	if !models.IsValidArtifactArch(arch) {
+		return "", "", errors.New("不支持的架构: " + arch)
+	}
+	return platform, arch, nil
+}
+
+// DeleteArtifact 删除版本的构建产物
+func (s *ArtifactService) DeleteArtifact(appID, versionID, artifactID uint) error {
+	var artifact models.VersionArtifact
+	if err := config.DB.Where("id = ? AND app_id = ? AND version_id = ?", artifactID, appID, versionID).
+		First(&artifact).Error; err != nil {
+		return errors.New("构建产物不存在")
+	}
+	if err := config.DB.Delete(&artifact).Error; err != nil {
+		return err
+	}
+	if artifact.IsHosted() {
+		s.deleteObject(artifact.StorageKey)
+	}
+
+	// 清除版本缓存
+	s.cacheService.ClearVersionCache()
+
+	return nil
+}
+
+// FindArtifact 查找版本中与客户端平台匹配的构建产物，没有匹配时返回 nil
+func (s *ArtifactService) FindArtifact(versionID uint, platform, arch string) (*models.VersionArtifact, error) {
+	platform, arch = models.ParseArtifactTarget(platform, arch)
+	if platform == "" {
+		return nil, nil
+	}
+
+	var artifacts []models.VersionArtifact
+	if err := config.DB.Where("version_id = ? AND platform = ?", versionID, platform).Find(&artifacts).Error; err != nil {
+		return nil, err
+	}
+	return models.MatchArtifact(artifacts, arch), nil
+}
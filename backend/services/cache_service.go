@@ -58,9 +58,9 @@ func (c *CacheService) SetApplicationCache(id string, data []byte) error {
 	return config.SetCache(key, string(data), 10*time.Minute)
 }
 
-// GetMemberLevelsCache 获取会员等级缓存
-func (c *CacheService) GetMemberLevelsCache() ([]byte, error) {
-	key := c.GenerateKey("member", "levels")
+// GetMemberLevelsCache 获取应用的会员等级缓存
+func (c *CacheService) GetMemberLevelsCache(appID string) ([]byte, error) {
+	key := c.GenerateKey("member:levels", appID)
 	data, err := config.GetCache(key)
 	if err != nil {
 		return nil, err
@@ -68,9 +68,9 @@ func (c *CacheService) GetMemberLevelsCache() ([]byte, error) {
 	return []byte(data), nil
 }
 
-// SetMemberLevelsCache 设置会员等级缓存
-func (c *CacheService) SetMemberLevelsCache(data []byte) error {
-	key := c.GenerateKey("member", "levels")
+// SetMemberLevelsCache 设置应用的会员等级缓存
+func (c *CacheService) SetMemberLevelsCache(appID string, data []byte) error {
+	key := c.GenerateKey("member:levels", appID)
 	return config.SetCache(key, string(data), 30*time.Minute)
 }
 
@@ -100,6 +100,11 @@ func (c *CacheService) ClearMemberCache() error {
 	return config.ClearCache("member:*")
 }
 
+// ClearMemberLevelsCache 清除应用的会员等级缓存
+func (c *CacheService) ClearMemberLevelsCache(appID string) error {
+	return config.DeleteCache(c.GenerateKey("member:levels", appID))
+}
+
 // ClearVersionCache 清除版本相关缓存
 func (c *CacheService) ClearVersionCache() error {
 	return config.ClearCache("versions:*")
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"app_management/config"
+	"app_management/models"
+	"app_management/utils"
+	"errors"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ChangelogService 结构化更新日志服务
+type ChangelogService struct{}
+
+// NewChangelogService 创建更新日志服务实例
+func NewChangelogService() *ChangelogService {
+	return &ChangelogService{}
+}
+
+// GetEntries 获取版本的更新日志条目
+func (s *ChangelogService) GetEntries(versionID uint) ([]models.ChangelogEntry, error) {
+	var entries []models.ChangelogEntry
+	result := config.DB.Where("version_id = ?", versionID).Order("position ASC").Find(&entries)
+	return entries, result.Error
+}
+
+// GetEntriesByVersion 批量获取多个版本的更新日志条目，按版本ID分组
+func (s *ChangelogService) GetEntriesByVersion(versionIDs []uint) (map[uint][]models.ChangelogEntry, error) {
+	grouped := make(map[uint][]models.ChangelogEntry)
+	if len(versionIDs) == 0 {
+		return grouped, nil
+	}
+
+	var entries []models.ChangelogEntry
+	if err := config.DB.Where("version_id IN ?", versionIDs).Order("position ASC").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		grouped[entry.VersionID] = append(grouped[entry.VersionID], entry)
+	}
+	return grouped, nil
+}
+
+// GetLocalizedChangelogs 获取版本的多语言更新日志
+func (s *ChangelogService) GetLocalizedChangelogs(versionID uint) ([]models.LocalizedChangelog, error) {
+	var changelogs []models.LocalizedChangelog
+	result := config.DB.Where("version_id = ?", versionID).Order("locale ASC").Find(&changelogs)
+	return changelogs, result.Error
+}
+
+// ResolveChangelog 按回退链选择版本的更新日志语言，均不存在时返回 nil，表示使用版本的默认更新日志
+func (s *ChangelogService) ResolveChangelog(versionID uint, chain []string) (*models.LocalizedChangelog, error) {
+	if len(chain) == 0 {
+		return nil, nil
+	}
+
+	changelogs, err := s.GetLocalizedChangelogs(versionID)
+	if err != nil {
+		return nil, err
+	}
+	for _, locale := range chain {
+		for i := range changelogs {
+			if changelogs[i].Locale == locale {
+				return &changelogs[i], nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// buildChangelogEntries 校验条目请求并转换为条目模型
+func buildChangelogEntries(reqs []models.ChangelogEntryRequest) ([]models.ChangelogEntry, error) {
+	entries := make([]models.ChangelogEntry, 0, len(reqs))
+	for i, req := range reqs {
+		if !models.IsValidChangelogCategory(req.Category) {
+			return nil, errors.New("不支持的更新日志分类: " + req.Category)
+		}
+		description := strings.TrimSpace(req.Description)
+		if description == "" {
+			return nil, errors.New("更新日志条目描述不能为空")
+		}
+		if len(description) > 1000 {
+			return nil, errors.New("更新日志条目描述不能超过1000个字符")
+		}
+
+		issues := models.IssueRefList{}
+		for _, issue := range req.Issues {
+			issue = strings.TrimSpace(issue)
+			if issue == "" {
+				continue
+			}
+			if strings.Contains(issue, ",") {
+				return nil, errors.New("关联问题不能包含逗号: " + issue)
+			}
+			issues = append(issues, strings.TrimPrefix(issue, "#"))
+		}
+		if len(strings.Join(issues, ",")) > 500 {
+			return nil, errors.New("关联问题过多")
+		}
+
+		entries = append(entries, models.ChangelogEntry{
+			Category:    req.Category,
+			Description: description,
+			Issues:      issues,
+			Position:    i,
+		})
+	}
+	return entries, nil
+}
+
+// replaceChangelogEntries 替换版本的全部更新日志条目，需在事务中调用
+func replaceChangelogEntries(tx *gorm.DB, versionID uint, entries []models.ChangelogEntry) error {
+	if err := tx.Where("version_id = ?", versionID).Delete(&models.ChangelogEntry{}).Error; err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	for i := range entries {
+		entries[i].ID = 0
+		entries[i].VersionID = versionID
+	}
+	return tx.Create(&entries).Error
+}
+
+// buildLocalizedChangelogs 校验语言标签并渲染多语言更新日志，内容为空的语言会被忽略
+func buildLocalizedChangelogs(changelogs map[string]string) ([]models.LocalizedChangelog, error) {
+	localized := make([]models.LocalizedChangelog, 0, len(changelogs))
+	seen := make(map[string]bool)
+	for key, md := range changelogs {
+		locale, err := utils.NormalizeLocale(key)
+		if err != nil {
+			return nil, err
+		}
+		if seen[locale] {
+			return nil, errors.New("重复的语言标签: " + key)
+		}
+		seen[locale] = true
+
+		if strings.TrimSpace(md) == "" {
+			continue
+		}
+		localized = append(localized, models.LocalizedChangelog{
+			Locale:        locale,
+			ChangelogMD:   md,
+			ChangelogHTML: utils.RenderMarkdown(md),
+		})
+	}
+	sort.Slice(localized, func(i, j int) bool {
+		return localized[i].Locale < localized[j].Locale
+	})
+	return localized, nil
+}
+
+// replaceLocalizedChangelogs 替换版本的全部多语言更新日志，需在事务中调用
+func replaceLocalizedChangelogs(tx *gorm.DB, versionID uint, changelogs []models.LocalizedChangelog) error {
+	if err := tx.Where("version_id = ?", versionID).Delete(&models.LocalizedChangelog{}).Error; err != nil {
+		return err
+	}
+	if len(changelogs) == 0 {
+		return nil
+	}
+	for i := range changelogs {
+		changelogs[i].ID = 0
+		changelogs[i].VersionID = versionID
+	}
+	return tx.Create(&changelogs).Error
+}
+
+// hasSecurityEntry 检查条目中是否包含安全修复
+func hasSecurityEntry(entries []models.ChangelogEntry) bool {
+	for _, entry := range entries {
+		if entry.Category == models.ChangelogSecurity {
+			return true
+		}
+	}
+	return false
+}
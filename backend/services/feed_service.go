@@ -0,0 +1,120 @@
+package services
+
+import (
+	"app_management/config"
+	"app_management/feeds"
+	"app_management/models"
+	"app_management/utils/semver"
+	"errors"
+	"sort"
+)
+
+// defaultFeedLimit 更新源默认包含的版本数量
+const defaultFeedLimit = 20
+
+// FeedService 更新源服务，为 Sparkle 等更新框架提供版本列表
+type FeedService struct {
+	updateService *UpdateService
+}
+
+// NewFeedService 创建更新源服务实例
+func NewFeedService() *FeedService {
+	return &FeedService{
+		updateService: NewUpdateService(),
+	}
+}
+
+// GetReleases 获取客户端在指定渠道可见的版本，从新到旧排列，并附带构建产物
+// 指定平台时同时选出与平台和架构匹配的产物
+func (s *FeedService) GetReleases(appID uint, channel, clientID, platform, arch string, limit int) ([]feeds.Release, error) {
+	channels := models.VisibleChannels(channel)
+	if channels == nil {
+		return nil, errors.New("无效的发布渠道")
+	}
+	if limit <= 0 {
+		limit = defaultFeedLimit
+	}
+
+	versions, err := s.updateService.clientVersions(appID, channels, clientID)
+	if err != nil {
+		return nil, err
+	}
+	sortVersionsDesc(versions)
+	if len(versions) > limit {
+		versions = versions[:limit]
+	}
+
+	// 一次查询所有版本的构建产物
+	versionIDs := make([]uint, len(versions))
+	for i, v := range versions {
+		versionIDs[i] = v.ID
+	}
+	var artifacts []models.VersionArtifact
+	if len(versionIDs) > 0 {
+		if err := config.DB.Where("version_id IN ?", versionIDs).Order("platform ASC, arch ASC").Find(&artifacts).Error; err != nil {
+			return nil, err
+		}
+	}
+	artifactsByVersion := make(map[uint][]models.VersionArtifact)
+	for _, artifact := range artifacts {
+		artifactsByVersion[artifact.VersionID] = append(artifactsByVersion[artifact.VersionID], artifact)
+	}
+
+	platform, arch = models.ParseArtifactTarget(platform, arch)
+	releases := make([]feeds.Release, 0, len(versions))
+	for _, v := range versions {
+		release := feeds.Release{
+			Version:       v.Version,
+			Channel:       v.Channel,
+			Mandatory:     v.ForceUpdate,
+			NotesMarkdown: v.ChangelogMD,
+			NotesHTML:     v.ChangelogHTML,
+			PublishedAt:   v.ReleasedAt(),
+			UpdatedAt:     v.UpdatedAt,
+			Artifacts:     artifactsByVersion[v.ID],
+		}
+		if platform != "" {
+			var candidates []models.VersionArtifact
+			for _, artifact := range release.Artifacts {
+				if artifact.Platform == platform {
+					candidates = append(candidates, artifact)
+				}
+			}
+			release.Artifact = models.MatchArtifact(candidates, arch)
+		}
+		releases = append(releases, release)
+	}
+	return releases, nil
+}
+
+// sortVersionsDesc 按语义化版本从高到低排序，无法解析的版本排在最后
+func sortVersionsDesc(versions []models.Version) {
+	parsed := make(map[string]*semver.Version, len(versions))
+	for _, v := range versions {
+		if p, err := semver.Parse(v.Version); err == nil {
+			parsed[v.Version] = p
+		}
+	}
+
+	sort.SliceStable(versions, func(i, j int) bool {
+		a, b := parsed[versions[i].Version], parsed[versions[j].Version]
+		if a == nil || b == nil {
+			return a != nil
+		}
+		return a.GreaterThan(b)
+	})
+}
+
+// GetPublicReleases 获取公开订阅源的应用及其稳定版版本，未开启公开订阅源的应用视为不存在
+func (s *FeedService) GetPublicReleases(appID uint) (*models.Application, []feeds.Release, error) {
+	var app models.Application
+	if err := config.DB.First(&app, appID).Error; err != nil || !app.PublicFeedEnabled {
+		return nil, nil, errors.New("应用不存在")
+	}
+
+	releases, err := s.GetReleases(appID, models.ChannelStable, "", "", "", 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &app, releases, nil
+}
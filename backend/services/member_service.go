@@ -4,7 +4,15 @@ import (
 	"encoding/json"
 	"app_management/config"
 	"app_management/models"
+	"app_management/utils"
 	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
 )
 
 // MemberService 会员服务
@@ -19,10 +27,12 @@ func NewMemberService() *MemberService {
 	}
 }
 
-// GetMemberLevels 获取会员等级列表
+// GetMemberLevels 获取应用的会员等级列表
 func (s *MemberService) GetMemberLevels(appID uint) ([]models.MemberLevel, error) {
+	cacheKey := strconv.FormatUint(uint64(appID), 10)
+
 	// 尝试从缓存获取
-	if cachedData, err := s.cacheService.GetMemberLevelsCache(); err == nil {
+	if cachedData, err := s.cacheService.GetMemberLevelsCache(cacheKey); err == nil {
 		var levels []models.MemberLevel
 		if json.Unmarshal(cachedData, &levels) == nil {
 			return levels, nil
@@ -38,39 +48,262 @@ func (s *MemberService) GetMemberLevels(appID uint) ([]models.MemberLevel, error
 
 	// 缓存数据
 	if data, err := json.Marshal(levels); err == nil {
-		s.cacheService.SetMemberLevelsCache(data)
+		s.cacheService.SetMemberLevelsCache(cacheKey, data)
 	}
 
 	return levels, nil
 }
 
-// UpdateMemberLevels 更新会员等级
-func (s *MemberService) UpdateMemberLevels(levels []models.MemberLevel) error {
-	// 验证JSON格式
-	for _, level := range levels {
-		if level.Permissions != "" {
-			var js json.RawMessage
-			if err := json.Unmarshal([]byte(level.Permissions), &js); err != nil {
-				return errors.New("权限配置JSON格式错误")
+// UpdateMemberLevels 整体替换应用的会员等级，不影响其他应用
+func (s *MemberService) UpdateMemberLevels(appID uint, reqs []models.MemberLevelRequest) ([]models.MemberLevel, error) {
+	var app models.Application
+	if err := config.DB.First(&app, appID).Error; err != nil {
+		return nil, errors.New("应用不存在")
+	}
+
+	schema, err := loadPermissionSchema(appID)
+	if err != nil {
+		return nil, err
+	}
+
+	levels := make([]models.MemberLevel, 0, len(reqs))
+	names := make(map[string]bool)
+	ordinals := make(map[int]bool)
+	for i, req := range reqs {
+		name := strings.TrimSpace(req.Name)
+		if name == "" {
+			return nil, errors.New("会员等级名称不能为空")
+		}
+		if utf8.RuneCountInString(name) > 20 {
+			return nil, errors.New("会员等级名称不能超过20个字符")
+		}
+		if names[name] {
+			return nil, fmt.Errorf("会员等级名称重复: %s", name)
+		}
+		if ordinals[req.Level] {
+			return nil, fmt.Errorf("会员等级重复: %d", req.Level)
+		}
+		names[name] = true
+		ordinals[req.Level] = true
+
+		// 验证JSON格式，未配置权限时使用空对象
+		permissions, err := req.PermissionsDocument()
+		if err != nil {
+			return nil, fmt.Errorf("levels[%d].permissions: 权限配置格式错误", i)
+		}
+		if permissions == "" {
+			permissions = "{}"
+		}
+		var js json.RawMessage
+		if err := json.Unmarshal([]byte(permissions), &js); err != nil {
+			return nil, fmt.Errorf("levels[%d].permissions: 会员等级 %s 的权限配置JSON格式错误", i, name)
+		}
+
+		levels = append(levels, models.MemberLevel{
+			AppID:       appID,
+			Name:        name,
+			Level:       req.Level,
+			Permissions: permissions,
+			ParentLevel: req.ParentLevel,
+			Standalone:  req.Standalone,
+		})
+	}
+
+	// 检查继承关系
+	for i, level := range levels {
+		if level.Standalone || level.ParentLevel == nil {
+			continue
+		}
+		if *level.ParentLevel == level.Level {
+			return nil, fmt.Errorf("levels[%d].parentLevel: 会员等级不能继承自身", i)
+		}
+		if !ordinals[*level.ParentLevel] {
+			return nil, fmt.Errorf("levels[%d].parentLevel: 继承的会员等级 %d 不存在", i, *level.ParentLevel)
+		}
+	}
+	effective, _, err := resolveEffectivePermissions(levels)
+	if err != nil {
+		return nil, err
+	}
+
+	// 应用注册了 Schema 时按 Schema 校验合并继承后的权限配置
+	if schema != nil {
+		var fieldErrors []models.FieldError
+		for i, level := range levels {
+			errs, err := validatePermissions(schema, i, effective[level.Level])
+			if err != nil {
+				return nil, err
+			}
+			fieldErrors = append(fieldErrors, errs...)
+		}
+		if len(fieldErrors) > 0 {
+			return nil, &PermissionValidationError{Errors: fieldErrors}
+		}
+	}
+	sort.Slice(levels, func(i, j int) bool {
+		return levels[i].Level < levels[j].Level
+	})
+
+	// 在事务中只替换该应用的会员等级，相同等级保留原有ID，会员订阅按ID关联等级
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		var existing []models.MemberLevel
+		if err := tx.Where("app_id = ?", appID).Find(&existing).Error; err != nil {
+			return err
+		}
+		existingByLevel := make(map[int]models.MemberLevel, len(existing))
+		for _, level := range existing {
+			existingByLevel[level.Level] = level
+		}
+
+		var removed []uint
+		for _, level := range existing {
+			if !ordinals[level.Level] {
+				removed = append(removed, level.ID)
+			}
+		}
+		if len(removed) > 0 {
+			var count int64
+			if err := tx.Model(&models.AppMember{}).Where("member_level_id IN ?", removed).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return errors.New("要删除的会员等级仍有会员使用，请先调整这些会员的等级")
+			}
+			if err := tx.Unscoped().Delete(&models.MemberLevel{}, removed).Error; err != nil {
+				return err
 			}
 		}
+
+		for i := range levels {
+			if old, ok := existingByLevel[levels[i].Level]; ok {
+				levels[i].ID = old.ID
+				levels[i].CreatedAt = old.CreatedAt
+			}
+			if err := tx.Save(&levels[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 清除该应用的会员等级缓存
+	s.cacheService.ClearMemberLevelsCache(strconv.FormatUint(uint64(appID), 10))
+
+	return levels, nil
+}
+
+// GetEffectiveMemberLevels 获取应用各会员等级合并继承后的权限配置
+func (s *MemberService) GetEffectiveMemberLevels(appID uint) ([]models.EffectiveMemberLevel, error) {
+	levels, err := s.GetMemberLevels(appID)
+	if err != nil {
+		return nil, err
 	}
 
-	// 清空现有数据并重新插入
-	if err := config.DB.Exec("DELETE FROM member_levels").Error; err != nil {
-		return err
+	effective, chains, err := resolveEffectivePermissions(levels)
+	if err != nil {
+		return nil, err
 	}
 
+	result := make([]models.EffectiveMemberLevel, 0, len(levels))
 	for _, level := range levels {
-		if err := config.DB.Create(&level).Error; err != nil {
-			return err
+		result = append(result, models.EffectiveMemberLevel{
+			ID:          level.ID,
+			Name:        level.Name,
+			Level:       level.Level,
+			Inherits:    chains[level.Level],
+			Permissions: json.RawMessage(effective[level.Level]),
+		})
+	}
+	return result, nil
+}
+
+// resolveEffectivePermissions 按继承关系合并会员等级的权限配置
+// 返回以等级为键的合并后权限配置JSON和继承链
+func resolveEffectivePermissions(levels []models.MemberLevel) (map[int]string, map[int][]int, error) {
+	byLevel := make(map[int]models.MemberLevel, len(levels))
+	ordinals := make([]int, 0, len(levels))
+	for _, level := range levels {
+		byLevel[level.Level] = level
+		ordinals = append(ordinals, level.Level)
+	}
+	sort.Ints(ordinals)
+
+	// parentOf 返回继承的等级，显式指定优先，否则为相邻的较低等级
+	parentOf := func(level models.MemberLevel) (int, bool, error) {
+		if level.Standalone {
+			return 0, false, nil
+		}
+		if level.ParentLevel != nil {
+			if _, ok := byLevel[*level.ParentLevel]; !ok || *level.ParentLevel == level.Level {
+				return 0, false, fmt.Errorf("会员等级 %d 继承的等级 %d 不存在", level.Level, *level.ParentLevel)
+			}
+			return *level.ParentLevel, true, nil
 		}
+		idx := sort.SearchInts(ordinals, level.Level)
+		if idx == 0 {
+			return 0, false, nil
+		}
+		return ordinals[idx-1], true, nil
 	}
 
-	// 清除会员相关缓存
-	s.cacheService.ClearMemberCache()
+	resolved := make(map[int]interface{}, len(levels))
+	chains := make(map[int][]int, len(levels))
+	visiting := make(map[int]bool)
+	var resolve func(ordinal int) (interface{}, error)
+	resolve = func(ordinal int) (interface{}, error) {
+		if doc, ok := resolved[ordinal]; ok {
+			return doc, nil
+		}
+		if visiting[ordinal] {
+			return nil, fmt.Errorf("会员等级 %d 的继承关系存在循环", ordinal)
+		}
+		visiting[ordinal] = true
+		defer delete(visiting, ordinal)
+
+		level := byLevel[ordinal]
+		var own interface{} = map[string]interface{}{}
+		if level.Permissions != "" {
+			decoder := json.NewDecoder(strings.NewReader(level.Permissions))
+			decoder.UseNumber()
+			if err := decoder.Decode(&own); err != nil {
+				return nil, fmt.Errorf("会员等级 %s 的权限配置JSON格式错误", level.Name)
+			}
+		}
+
+		chain := []int{}
+		parent, ok, err := parentOf(level)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			base, err := resolve(parent)
+			if err != nil {
+				return nil, err
+			}
+			own = utils.MergePermissions(base, own)
+			chain = append(append(chain, parent), chains[parent]...)
+		}
+		resolved[ordinal] = own
+		chains[ordinal] = chain
+		return own, nil
+	}
 
-	return nil
+	effective := make(map[int]string, len(levels))
+	for _, ordinal := range ordinals {
+		doc, err := resolve(ordinal)
+		if err != nil {
+			return nil, nil, err
+		}
+		data, err := json.Marshal(doc)
+		if err != nil {
+			return nil, nil, err
+		}
+		effective[ordinal] = string(data)
+	}
+	return effective, chains, nil
 }
 
 // CreateAuditLog 创建审计日志
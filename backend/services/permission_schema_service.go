@@ -0,0 +1,143 @@
+package services
+
+import (
+	"app_management/config"
+	"app_management/models"
+	"app_management/utils"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"gorm.io/gorm"
+)
+
+// maxPermissionSchemaSize 权限配置 Schema 的最大长度
+const maxPermissionSchemaSize = 64 * 1024
+
+// PermissionValidationError 会员权限配置不符合应用的 Schema
+type PermissionValidationError struct {
+	Errors []models.FieldError
+}
+
+// Error 实现 error 接口，返回第一个错误
+func (e *PermissionValidationError) Error() string {
+	if len(e.Errors) == 0 {
+		return "权限配置不符合Schema"
+	}
+	return fmt.Sprintf("%s: %s", e.Errors[0].Field, e.Errors[0].Message)
+}
+
+// PermissionSchemaService 会员权限配置 Schema 服务
+type PermissionSchemaService struct{}
+
+// NewPermissionSchemaService 创建权限配置 Schema 服务实例
+func NewPermissionSchemaService() *PermissionSchemaService {
+	return &PermissionSchemaService{}
+}
+
+// GetSchema 获取应用注册的 Schema，未注册时返回 gorm.ErrRecordNotFound
+func (s *PermissionSchemaService) GetSchema(appID uint) (*models.PermissionSchema, error) {
+	var schema models.PermissionSchema
+	if err := config.DB.Where("app_id = ?", appID).First(&schema).Error; err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+// SetSchema 注册或替换应用的 Schema，已有会员等级必须符合新的 Schema
+func (s *PermissionSchemaService) SetSchema(appID uint, document json.RawMessage) (*models.PermissionSchema, error) {
+	var app models.Application
+	if err := config.DB.First(&app, appID).Error; err != nil {
+		return nil, errors.New("应用不存在")
+	}
+
+	if len(document) > maxPermissionSchemaSize {
+		return nil, errors.New("Schema不能超过64KB")
+	}
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, document); err != nil {
+		return nil, errors.New("JSON Schema格式错误")
+	}
+	compiled, err := utils.CompileJSONSchema(compacted.String())
+	if err != nil {
+		return nil, err
+	}
+
+	// 检查已有会员等级合并继承后的权限配置
+	var levels []models.MemberLevel
+	if err := config.DB.Where("app_id = ?", appID).Order("level ASC").Find(&levels).Error; err != nil {
+		return nil, err
+	}
+	effective, _, err := resolveEffectivePermissions(levels)
+	if err != nil {
+		return nil, err
+	}
+	var fieldErrors []models.FieldError
+	for i, level := range levels {
+		errs, err := validatePermissions(compiled, i, effective[level.Level])
+		if err != nil {
+			return nil, err
+		}
+		fieldErrors = append(fieldErrors, errs...)
+	}
+	if len(fieldErrors) > 0 {
+		return nil, &PermissionValidationError{Errors: fieldErrors}
+	}
+
+	schema := models.PermissionSchema{AppID: appID}
+	err = config.DB.Where("app_id = ?", appID).
+		Assign(models.PermissionSchema{Schema: json.RawMessage(compacted.Bytes())}).
+		FirstOrCreate(&schema).Error
+	if err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+// DeleteSchema 删除应用的 Schema，之后会员等级只校验JSON格式
+func (s *PermissionSchemaService) DeleteSchema(appID uint) error {
+	result := config.DB.Where("app_id = ?", appID).Delete(&models.PermissionSchema{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("应用未注册权限配置Schema")
+	}
+	return nil
+}
+
+// loadPermissionSchema 加载并编译应用的 Schema，未注册时返回 nil
+func loadPermissionSchema(appID uint) (*jsonschema.Schema, error) {
+	schema, err := NewPermissionSchemaService().GetSchema(appID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return utils.CompileJSONSchema(string(schema.Schema))
+}
+
+// validatePermissions 按 Schema 校验第 index 个会员等级的权限配置，错误字段以 levels[index].permissions 开头
+func validatePermissions(schema *jsonschema.Schema, index int, permissions string) ([]models.FieldError, error) {
+	violations, err := utils.ValidateJSONSchema(schema, permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := fmt.Sprintf("levels[%d].permissions", index)
+	fieldErrors := make([]models.FieldError, 0, len(violations))
+	for _, v := range violations {
+		field := prefix
+		if path := utils.JSONPointerToPath(v.Path); path != "" {
+			if path[0] != '[' {
+				field += "."
+			}
+			field += path
+		}
+		fieldErrors = append(fieldErrors, models.FieldError{Field: field, Message: v.Message})
+	}
+	return fieldErrors, nil
+}
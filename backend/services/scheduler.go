@@ -0,0 +1,88 @@
+package services
+
+import (
+	"app_management/config"
+	"app_management/models"
+	"encoding/json"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ReleaseScheduler 定时发布调度器，周期性发布已到发布时间的版本
+type ReleaseScheduler struct {
+	appService *AppService
+	interval   time.Duration
+	stop       chan struct{}
+	once       sync.Once
+}
+
+// NewReleaseScheduler 创建定时发布调度器实例
+func NewReleaseScheduler(appService *AppService, interval time.Duration) *ReleaseScheduler {
+	return &ReleaseScheduler{
+		appService: appService,
+		interval:   interval,
+		stop:       make(chan struct{}),
+	}
+}
+
+// Start 在后台启动调度器
+func (s *ReleaseScheduler) Start() {
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		// 启动时先处理一次，避免服务重启期间错过的版本延迟发布
+		s.RunOnce()
+		for {
+			select {
+			case <-ticker.C:
+				s.RunOnce()
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+	log.Printf("定时发布调度器已启动，检查间隔 %v", s.interval)
+}
+
+// Stop 停止调度器
+func (s *ReleaseScheduler) Stop() {
+	s.once.Do(func() {
+		close(s.stop)
+	})
+}
+
+// RunOnce 发布所有到期的定时版本并写入审计日志
+func (s *ReleaseScheduler) RunOnce() {
+	published, err := s.appService.PublishDueVersions()
+	if err != nil {
+		log.Printf("定时发布失败: %v", err)
+	}
+
+	for _, version := range published {
+		details, _ := json.Marshal(map[string]interface{}{
+			"appId":     version.AppID,
+			"version":   version.Version,
+			"channel":   version.Channel,
+			"publishAt": version.PublishAt,
+		})
+		now := time.Now()
+		auditLog := &models.AuditLog{
+			UserID:     "system",
+			UserName:   "scheduler",
+			Action:     "publish",
+			EntityType: "version",
+			EntityID:   strconv.FormatUint(uint64(version.ID), 10),
+			EntityName: version.Version,
+			Details:    string(details),
+			Timestamp:  now,
+			Status:     "success",
+		}
+		if err := config.DB.Create(auditLog).Error; err != nil {
+			log.Printf("写入定时发布审计日志失败: %v", err)
+		}
+		log.Printf("定时发布版本 %s (应用ID %d)", version.Version, version.AppID)
+	}
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"app_management/config"
+	"app_management/models"
+	"app_management/utils"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SigningService 更新清单签名服务
+type SigningService struct{}
+
+// NewSigningService 创建签名服务实例
+func NewSigningService() *SigningService {
+	return &SigningService{}
+}
+
+// GetSigningKeys 获取应用的全部签名密钥，按创建时间倒序
+func (s *SigningService) GetSigningKeys(appID uint) ([]models.SigningKey, error) {
+	var keys []models.SigningKey
+	result := config.DB.Where("app_id = ?", appID).Order("id DESC").Find(&keys)
+	return keys, result.Error
+}
+
+// GetPublicKeys 获取应用对外发布的公钥：当前密钥和宽限期内的旧密钥
+func (s *SigningService) GetPublicKeys(appID uint) ([]models.SigningKey, error) {
+	// 确保应用已有签名密钥
+	if _, err := s.activeKey(appID); err != nil {
+		return nil, err
+	}
+
+	keys, err := s.GetSigningKeys(appID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	published := make([]models.SigningKey, 0, len(keys))
+	for _, key := range keys {
+		if key.IsPublished(now) {
+			published = append(published, key)
+		}
+	}
+	return published, nil
+}
+
+// RotateSigningKey 生成新的签名密钥，旧密钥停止签名但在宽限期内继续发布公钥
+func (s *SigningService) RotateSigningKey(appID uint, gracePeriod time.Duration) (*models.SigningKey, error) {
+	var app models.Application
+	if err := config.DB.First(&app, appID).Error; err != nil {
+		return nil, errors.New("应用不存在")
+	}
+
+	key, err := newSigningKey(appID)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(gracePeriod)
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockApplication(tx, appID); err != nil {
+			return err
+		}
+		if err := tx.Model(&models.SigningKey{}).
+			Where("app_id = ? AND status = ?", appID, models.SigningKeyStatusActive).
+			Updates(map[string]interface{}{
+				"status":     models.SigningKeyStatusRetired,
+				"expires_at": expiresAt,
+			}).Error; err != nil {
+			return err
+		}
+		return tx.Create(key).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// Sign 使用应用当前密钥对数据的规范化JSON签名
+func (s *SigningService) Sign(appID uint, payload interface{}) (*models.ManifestSignature, error) {
+	key, err := s.activeKey(appID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := utils.CanonicalJSON(payload)
+	if err != nil {
+		return nil, err
+	}
+	privateKey, err := utils.OpenSecret(config.SigningKeySecret(), key.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := utils.SignEd25519(privateKey, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ManifestSignature{
+		KeyID:     key.KeyID,
+		Algorithm: key.Algorithm,
+		Signature: signature,
+	}, nil
+}
+
+// activeKey 获取应用当前的签名密钥，尚未生成时自动创建
+func (s *SigningService) activeKey(appID uint) (*models.SigningKey, error) {
+	var key models.SigningKey
+	err := config.DB.Where("app_id = ? AND status = ?", appID, models.SigningKeyStatusActive).
+		Order("id DESC").First(&key).Error
+	if err == nil {
+		return &key, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockApplication(tx, appID); err != nil {
+			return err
+		}
+
+		// 加锁后重新读取，并发的首次请求可能已经创建了密钥
+		err := tx.Where("app_id = ? AND status = ?", appID, models.SigningKeyStatusActive).
+			Order("id DESC").First(&key).Error
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		created, err := newSigningKey(appID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(created).Error; err != nil {
+			return err
+		}
+		key = *created
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// lockApplication 锁定应用记录，使同一应用的签名密钥创建和轮换串行执行
+func lockApplication(tx *gorm.DB, appID uint) error {
+	var app models.Application
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&app, appID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrAppNotFound
+	}
+	return err
+}
+
+// newSigningKey 生成新的 Ed25519 签名密钥
+func newSigningKey(appID uint) (*models.SigningKey, error) {
+	publicKey, privateKey, err := utils.GenerateSigningKey()
+	if err != nil {
+		return nil, errors.New("生成签名密钥失败")
+	}
+
+	// 私钥种子加密后存储
+	sealed, err := utils.SealSecret(config.SigningKeySecret(), privateKey)
+	if err != nil {
+		return nil, errors.New("加密签名密钥失败")
+	}
+
+	return &models.SigningKey{
+		AppID:      appID,
+		KeyID:      utils.SigningKeyID(publicKey),
+		Algorithm:  models.SignatureAlgorithmEd25519,
+		PublicKey:  publicKey,
+		PrivateKey: sealed,
+		Status:     models.SigningKeyStatusActive,
+	}, nil
+}
@@ -0,0 +1,312 @@
+package services
+
+import (
+	"app_management/config"
+	"app_management/models"
+	"app_management/utils"
+	"app_management/utils/semver"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// UpdateService 客户端更新检查服务
+type UpdateService struct {
+	artifactService  *ArtifactService
+	changelogService *ChangelogService
+}
+
+// NewUpdateService 创建更新检查服务实例
+func NewUpdateService() *UpdateService {
+	return &UpdateService{
+		artifactService:  NewArtifactService(),
+		changelogService: NewChangelogService(),
+	}
+}
+
+// CheckUpdate 检查客户端当前版本是否需要更新
+func (s *UpdateService) CheckUpdate(app *models.Application, req *models.UpdateCheckRequest) (*models.UpdateCheckResult, error) {
+	current, err := semver.Parse(req.Current)
+	if err != nil {
+		return nil, errors.New("当前版本号格式不正确")
+	}
+
+	channel := req.Channel
+	if channel == "" {
+		channel = models.ChannelStable
+	}
+	channels := models.VisibleChannels(channel)
+	if channels == nil {
+		return nil, errors.New("无效的发布渠道")
+	}
+
+	format := req.Format
+	if format == "" {
+		format = utils.ChangelogFormatHTML
+	}
+	if !utils.IsValidChangelogFormat(format) {
+		return nil, errors.New("无效的更新日志格式")
+	}
+	if req.Category != "" && !models.IsValidChangelogCategory(req.Category) {
+		return nil, errors.New("无效的更新日志分类")
+	}
+
+	versions, err := s.clientVersions(app.ID, channels, req.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.UpdateCheckResult{
+		CurrentVersion:      req.Current,
+		MinSupportedVersion: app.MinSupportedVersion,
+		Channel:             channel,
+		Platform:            req.Platform,
+		ChangelogFormat:     format,
+		Versions:            []models.UpdateVersionInfo{},
+	}
+	if latest := latestVersion(versions); latest != nil {
+		result.LatestVersion = latest.Version
+	}
+
+	// 找出所有高于当前版本的版本，按优先级从高到低排列
+	newer := newerVersions(versions, current)
+	if len(newer) == 0 {
+		return result, nil
+	}
+	result.UpdateAvailable = true
+
+	// 低于最低支持版本，或跳过了强制更新版本时必须更新
+	if app.MinSupportedVersion != "" {
+		if minSupported, err := semver.Parse(app.MinSupportedVersion); err == nil && current.LessThan(minSupported) {
+			result.Mandatory = true
+		}
+	}
+
+	// 指定分类时只保留该分类的条目
+	if req.Category != "" {
+		if err := s.filterChangelogs(newer, req.Category); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, v := range newer {
+		if v.ForceUpdate {
+			result.Mandatory = true
+		}
+		result.Versions = append(result.Versions, models.UpdateVersionInfo{
+			Version:     v.Version,
+			Channel:     v.Channel,
+			ForceUpdate: v.ForceUpdate,
+			Breaking:    v.Breaking,
+			Security:    v.Security,
+			Changelog:   utils.FormatChangelog(v.ChangelogMD, v.ChangelogHTML, format),
+			ReleasedAt:  v.ReleasedAt(),
+		})
+	}
+	combined := mergeChangelogs(newer)
+	result.Changelog = utils.FormatChangelog(combined, utils.RenderMarkdown(combined), format)
+
+	// 返回更新目标版本中与客户端平台匹配的构建产物
+	artifact, err := s.artifactService.FindArtifact(newer[0].ID, req.Platform, req.Arch)
+	if err != nil {
+		return nil, err
+	}
+	result.Artifact = artifact
+
+	return result, nil
+}
+
+// CompareVersions 获取 from（不含）到 to（含）之间已发布的版本及合并后的更新日志
+func (s *UpdateService) CompareVersions(appID uint, req *models.CompareVersionsRequest) (*models.VersionComparison, error) {
+	from, err := semver.Parse(req.From)
+	if err != nil {
+		return nil, errors.New("起始版本号格式不正确")
+	}
+	to, err := semver.Parse(req.To)
+	if err != nil {
+		return nil, errors.New("目标版本号格式不正确")
+	}
+	if !to.GreaterThan(from) {
+		return nil, errors.New("目标版本必须高于起始版本")
+	}
+
+	channel := req.Channel
+	if channel == "" {
+		channel = models.ChannelStable
+	}
+	channels := models.VisibleChannels(channel)
+	if channels == nil {
+		return nil, errors.New("无效的发布渠道")
+	}
+
+	var versions []models.Version
+	if err := config.DB.Where("app_id = ? AND channel IN ? AND publish_status = ? AND yanked = ?",
+		appID, channels, models.PublishStatusPublished, false).Find(&versions).Error; err != nil {
+		return nil, err
+	}
+
+	// 筛选区间内的版本，按从旧到新排列
+	var inRange []models.Version
+	for _, v := range newerVersions(versions, from) {
+		if parsed, err := semver.Parse(v.Version); err == nil && !parsed.GreaterThan(to) {
+			inRange = append(inRange, v)
+		}
+	}
+	for i, j := 0, len(inRange)-1; i < j; i, j = i+1, j-1 {
+		inRange[i], inRange[j] = inRange[j], inRange[i]
+	}
+
+	result := &models.VersionComparison{
+		From:             req.From,
+		To:               req.To,
+		Channel:          channel,
+		Versions:         []models.ComparedVersion{},
+		BreakingVersions: []string{},
+		SecurityVersions: []string{},
+	}
+	for _, v := range inRange {
+		result.Versions = append(result.Versions, models.ComparedVersion{
+			Version:       v.Version,
+			Channel:       v.Channel,
+			ForceUpdate:   v.ForceUpdate,
+			Breaking:      v.Breaking,
+			Security:      v.Security,
+			ChangelogMD:   v.ChangelogMD,
+			ChangelogHTML: v.ChangelogHTML,
+			ReleasedAt:    v.ReleasedAt(),
+		})
+		if v.Breaking {
+			result.Breaking = true
+			result.BreakingVersions = append(result.BreakingVersions, v.Version)
+		}
+		if v.Security {
+			result.Security = true
+			result.SecurityVersions = append(result.SecurityVersions, v.Version)
+		}
+	}
+	result.ChangelogMD = mergeChangelogs(inRange)
+	result.ChangelogHTML = utils.RenderMarkdown(result.ChangelogMD)
+
+	return result, nil
+}
+
+// filterChangelogs 将版本的更新日志替换为指定分类条目生成的内容，没有结构化条目的版本更新日志为空
+func (s *UpdateService) filterChangelogs(versions []models.Version, category string) error {
+	versionIDs := make([]uint, len(versions))
+	for i, v := range versions {
+		versionIDs[i] = v.ID
+	}
+	entries, err := s.changelogService.GetEntriesByVersion(versionIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range versions {
+		versions[i].Entries = models.FilterChangelogEntries(entries[versions[i].ID], category)
+		versions[i].ChangelogMD = models.ChangelogMarkdown(versions[i].Entries)
+		versions[i].ChangelogHTML = utils.RenderMarkdown(versions[i].ChangelogMD)
+	}
+	return nil
+}
+
+// mergeChangelogs 按给定顺序合并多个版本的更新日志，每个版本一个二级标题，跳过没有更新日志的版本
+func mergeChangelogs(versions []models.Version) string {
+	changelogs := make([]string, 0, len(versions))
+	for _, v := range versions {
+		if strings.TrimSpace(v.ChangelogMD) == "" {
+			continue
+		}
+		changelogs = append(changelogs, fmt.Sprintf("## %s\n\n%s", v.Version, strings.TrimSpace(v.ChangelogMD)))
+	}
+	return strings.Join(changelogs, "\n\n")
+}
+
+// GetLatestForClient 获取指定客户端在某渠道可以获取的最新版本，考虑灰度发布
+func (s *UpdateService) GetLatestForClient(appID uint, channel, clientID string) (*models.Version, error) {
+	channels := models.VisibleChannels(channel)
+	if channels == nil {
+		return nil, errors.New("无效的发布渠道")
+	}
+
+	versions, err := s.clientVersions(appID, channels, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := latestVersion(versions)
+	if latest == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return latest, nil
+}
+
+// clientVersions 获取客户端在指定渠道中可见的版本，未发布和已撤回的版本不可见
+func (s *UpdateService) clientVersions(appID uint, channels []string, clientID string) ([]models.Version, error) {
+	var versions []models.Version
+	if err := config.DB.Where("app_id = ? AND channel IN ? AND publish_status = ?", appID, channels, models.PublishStatusPublished).
+		Find(&versions).Error; err != nil {
+		return nil, err
+	}
+
+	available := make([]models.Version, 0, len(versions))
+	for _, v := range versions {
+		if !v.Yanked && isRolledOutTo(&v, clientID) {
+			available = append(available, v)
+		}
+	}
+	return available, nil
+}
+
+// isRolledOutTo 检查版本是否已推送给指定客户端
+// 未提供客户端ID时只能获取全量发布的版本；暂停时已分到的客户端仍可获取，终止后所有客户端都不可获取
+func isRolledOutTo(v *models.Version, clientID string) bool {
+	if v.RolloutStatus == models.RolloutStatusHalted {
+		return false
+	}
+	if v.RolloutPercentage >= 100 {
+		return true
+	}
+	if v.RolloutPercentage <= 0 || clientID == "" {
+		return false
+	}
+	return rolloutBucket(v.AppID, v.Version, clientID) < v.RolloutPercentage
+}
+
+// rolloutBucket 将客户端确定性地分配到0-99的桶中，同一客户端在同一版本上的桶号固定
+// 不同版本的分桶相互独立，避免总是同一批用户先收到更新
+func rolloutBucket(appID uint, version, clientID string) int {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d:%s:%s", appID, version, clientID)))
+	return int(binary.BigEndian.Uint64(sum[:8]) % 100)
+}
+
+// newerVersions 返回优先级高于 current 的版本，按优先级从高到低排列
+func newerVersions(versions []models.Version, current *semver.Version) []models.Version {
+	type parsedVersion struct {
+		version models.Version
+		semver  *semver.Version
+	}
+
+	var newer []parsedVersion
+	for _, v := range versions {
+		parsed, err := semver.Parse(v.Version)
+		if err != nil || !parsed.GreaterThan(current) {
+			continue
+		}
+		newer = append(newer, parsedVersion{version: v, semver: parsed})
+	}
+
+	sort.SliceStable(newer, func(i, j int) bool {
+		return newer[i].semver.GreaterThan(newer[j].semver)
+	})
+
+	result := make([]models.Version, len(newer))
+	for i, v := range newer {
+		result[i] = v.version
+	}
+	return result
+}
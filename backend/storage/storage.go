@@ -0,0 +1,79 @@
+// Package storage 提供构建产物文件的存储接口及其文件系统、S3兼容实现
+package storage
+
+import (
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// ErrNotFound 对象不存在
+var ErrNotFound = errors.New("对象不存在")
+
+// Object 已存储对象的元数据
+type Object struct {
+	Key     string
+	Size    int64
+	SHA256  string // 上传时计算的十六进制SHA-256，Open 时可能为空
+	SHA1    string // 上传时计算的十六进制SHA-1，Squirrel.Windows 的 RELEASES 文件需要
+	ModTime time.Time
+}
+
+// Storage 构建产物存储接口
+type Storage interface {
+	// Put 以流式方式写入对象，同时计算大小和SHA-256
+	Put(ctx context.Context, key string, r io.Reader) (*Object, error)
+	// Open 打开对象用于读取，返回的读取器支持 Seek 以便处理 Range 请求
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, *Object, error)
+	// Delete 删除对象，对象不存在时不返回错误
+	Delete(ctx context.Context, key string) error
+}
+
+// CleanKey 规范化对象键，拒绝空键和目录穿越
+func CleanKey(key string) (string, error) {
+	cleaned := path.Clean("/" + strings.ReplaceAll(key, "\\", "/"))
+	cleaned = strings.TrimPrefix(cleaned, "/")
+	if cleaned == "" || cleaned == "." {
+		return "", errors.New("对象键不能为空")
+	}
+	return cleaned, nil
+}
+
+// hashingReader 在读取过程中累计大小、SHA-256 和 SHA-1
+type hashingReader struct {
+	r      io.Reader
+	sha256 hash.Hash
+	sha1   hash.Hash
+	size   int64
+}
+
+func newHashingReader(r io.Reader) *hashingReader {
+	return &hashingReader{r: r, sha256: sha256.New(), sha1: sha1.New()}
+}
+
+func (r *hashingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.sha256.Write(p[:n])
+		r.sha1.Write(p[:n])
+		r.size += int64(n)
+	}
+	return n, err
+}
+
+// Sum 返回已读取内容的十六进制SHA-256
+func (r *hashingReader) Sum() string {
+	return hex.EncodeToString(r.sha256.Sum(nil))
+}
+
+// SumSHA1 返回已读取内容的十六进制SHA-1
+func (r *hashingReader) SumSHA1() string {
+	return hex.EncodeToString(r.sha1.Sum(nil))
+}
@@ -2,9 +2,14 @@ package utils
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/hex"
 )
 
+// APIKeyPrefixLength API密钥明文前缀长度，用于展示和查找
+const APIKeyPrefixLength = 12
+
 // GenerateAPIKey 生成32字节的API密钥
 func GenerateAPIKey() (string, error) {
 	bytes := make([]byte, 32)
@@ -12,4 +17,23 @@ func GenerateAPIKey() (string, error) {
 		return "", err
 	}
 	return hex.EncodeToString(bytes), nil
-} 
\ No newline at end of file
+}
+
+// APIKeyPrefix 获取API密钥的可见前缀
+func APIKeyPrefix(apiKey string) string {
+	if len(apiKey) <= APIKeyPrefixLength {
+		return apiKey
+	}
+	return apiKey[:APIKeyPrefixLength]
+}
+
+// HashAPIKey 计算API密钥的SHA-256哈希，数据库中只保存哈希值
+func HashAPIKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyAPIKey 以常量时间比较API密钥与已保存的哈希
+func VerifyAPIKey(apiKey, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashAPIKey(apiKey)), []byte(hash)) == 1
+}
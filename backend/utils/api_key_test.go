@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAPIKeyHash 测试API密钥哈希与校验
+func TestAPIKeyHash(t *testing.T) {
+	apiKey, err := GenerateAPIKey()
+	assert.NoError(t, err)
+	assert.Len(t, apiKey, 64)
+
+	hash := HashAPIKey(apiKey)
+	assert.NotEqual(t, apiKey, hash)
+	assert.True(t, VerifyAPIKey(apiKey, hash))
+
+	otherKey, _ := GenerateAPIKey()
+	assert.False(t, VerifyAPIKey(otherKey, hash))
+	assert.False(t, VerifyAPIKey(apiKey, ""))
+
+	assert.Equal(t, apiKey[:APIKeyPrefixLength], APIKeyPrefix(apiKey))
+	assert.Equal(t, "short", APIKeyPrefix("short"))
+}
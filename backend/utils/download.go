@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignDownloadToken 生成构建产物的限时下载令牌，格式为 "应用ID.过期时间戳.签名"
+// 更新源中的下载地址使用该令牌认证，避免在响应中回显API密钥
+func SignDownloadToken(secret string, appID, artifactID uint, expiresAt time.Time) string {
+	payload := fmt.Sprintf("%d.%d", appID, expiresAt.Unix())
+	return payload + "." + downloadTokenSignature(secret, payload, artifactID)
+}
+
+// ParseDownloadToken 校验下载令牌，令牌须属于指定构建产物且未过期，返回签发令牌的应用ID
+func ParseDownloadToken(secret, token string, artifactID uint, now time.Time) (uint, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return 0, errors.New("无效的下载令牌")
+	}
+	appID, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, errors.New("无效的下载令牌")
+	}
+	expiresAt, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, errors.New("无效的下载令牌")
+	}
+
+	expected := downloadTokenSignature(secret, parts[0]+"."+parts[1], artifactID)
+	if !hmac.Equal([]byte(parts[2]), []byte(expected)) {
+		return 0, errors.New("无效的下载令牌")
+	}
+	if now.Unix() > expiresAt {
+		return 0, errors.New("下载令牌已过期")
+	}
+	return uint(appID), nil
+}
+
+// downloadTokenSignature 计算令牌签名，签名内容包含构建产物ID，令牌不能用于下载其他产物
+func downloadTokenSignature(secret, payload string, artifactID uint) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%s.%d", payload, artifactID)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDownloadToken 测试构建产物的限时下载令牌
+func TestDownloadToken(t *testing.T) {
+	now := time.Now()
+	token := SignDownloadToken("secret", 7, 42, now.Add(time.Hour))
+
+	appID, err := ParseDownloadToken("secret", token, 42, now)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(7), appID)
+
+	// 其他构建产物、错误密钥、过期和被篡改的令牌均无效
+	_, err = ParseDownloadToken("secret", token, 43, now)
+	assert.Error(t, err)
+	_, err = ParseDownloadToken("other", token, 42, now)
+	assert.Error(t, err)
+	_, err = ParseDownloadToken("secret", token, 42, now.Add(2*time.Hour))
+	assert.Error(t, err)
+	_, err = ParseDownloadToken("secret", "8"+token[1:], 42, now)
+	assert.Error(t, err)
+	_, err = ParseDownloadToken("secret", "invalid", 42, now)
+	assert.Error(t, err)
+}
@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// IsJSONPath 检查权益查询是否为JSON路径，支持 $.limits.api_calls、limits.api_calls、/limits/api_calls 及 features[0] 形式
+func IsJSONPath(query string) bool {
+	return strings.HasPrefix(query, "$") || strings.HasPrefix(query, "/") || strings.ContainsAny(query, ".[")
+}
+
+// LookupJSONPath 按路径查找权限配置中的值
+func LookupJSONPath(doc interface{}, path string) (interface{}, bool) {
+	tokens, ok := splitJSONPath(path)
+	if !ok {
+		return nil, false
+	}
+
+	current := doc
+	for _, token := range tokens {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, exists := node[token]
+			if !exists {
+				return nil, false
+			}
+			current = value
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			current = node[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// EvaluateEntitlement 根据权限配置判断权益是否可用，返回是否允许及对应的值
+//   - JSON路径按路径取值判断
+//   - 功能键出现在 restrictions 中时拒绝，出现在 features 中时允许，否则按同名的顶层字段判断
+//
+// 取到的值为 false、0、空字符串、空数组或 null 时拒绝
+func EvaluateEntitlement(doc interface{}, query string) (bool, interface{}) {
+	if IsJSONPath(query) {
+		value, ok := LookupJSONPath(doc, query)
+		if !ok {
+			return false, nil
+		}
+		return isTruthy(value), value
+	}
+
+	root, _ := doc.(map[string]interface{})
+	if containsString(root["restrictions"], query) {
+		return false, nil
+	}
+	if containsString(root["features"], query) {
+		return true, true
+	}
+	value, ok := root[query]
+	if !ok {
+		return false, nil
+	}
+	return isTruthy(value), value
+}
+
+// splitJSONPath 将路径拆分为字段名和数组下标
+func splitJSONPath(path string) ([]string, bool) {
+	if strings.HasPrefix(path, "/") {
+		var tokens []string
+		for _, token := range strings.Split(path[1:], "/") {
+			tokens = append(tokens, strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~"))
+		}
+		return tokens, true
+	}
+
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	if path == "" {
+		return nil, true
+	}
+	var tokens []string
+	for _, segment := range strings.Split(path, ".") {
+		// 处理 features[0] 形式的数组下标
+		name := segment
+		var indexes []string
+		if i := strings.IndexByte(segment, '['); i >= 0 {
+			name = segment[:i]
+			for _, part := range strings.Split(segment[i:], "[")[1:] {
+				if !strings.HasSuffix(part, "]") {
+					return nil, false
+				}
+				indexes = append(indexes, strings.TrimSuffix(part, "]"))
+			}
+		}
+		if name == "" && len(indexes) == 0 {
+			return nil, false
+		}
+		if name != "" {
+			tokens = append(tokens, name)
+		}
+		tokens = append(tokens, indexes...)
+	}
+	return tokens, true
+}
+
+// isTruthy 判断权限值是否表示开放
+func isTruthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case json.Number:
+		f, err := v.Float64()
+		return err == nil && f != 0
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	case []interface{}:
+		return len(v) > 0
+	}
+	return true
+}
+
+// containsString 检查JSON数组中是否包含指定字符串
+func containsString(list interface{}, s string) bool {
+	items, ok := list.([]interface{})
+	if !ok {
+		return false
+	}
+	for _, item := range items {
+		if str, ok := item.(string); ok && str == s {
+			return true
+		}
+	}
+	return false
+}
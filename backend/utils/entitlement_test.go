@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEvaluateEntitlement 测试按功能键和JSON路径判断权益
+func TestEvaluateEntitlement(t *testing.T) {
+	var doc interface{}
+	decoder := json.NewDecoder(strings.NewReader(`{
+		"features": ["basic", "export", "advanced"],
+		"restrictions": ["export"],
+		"limits": {"api_calls": 5000, "storage": "5GB", "projects": 0, "a/b": true},
+		"offline": true,
+		"beta": false,
+		"seats": [{"role": "admin"}]
+	}`))
+	decoder.UseNumber()
+	assert.NoError(t, decoder.Decode(&doc))
+
+	cases := []struct {
+		query   string
+		allowed bool
+		value   interface{}
+	}{
+		{"basic", true, true},
+		{"export", false, nil}, // restrictions 优先
+		{"enterprise", false, nil},
+		{"offline", true, true},
+		{"beta", false, false},
+		{"limits.api_calls", true, json.Number("5000")},
+		{"$.limits.storage", true, "5GB"},
+		{"/limits/projects", false, json.Number("0")},
+		{"/limits/a~1b", true, true},
+		{"seats[0].role", true, "admin"},
+		{"features[5]", false, nil},
+		{"limits.missing", false, nil},
+		{"limits[", false, nil},
+	}
+	for _, c := range cases {
+		allowed, value := EvaluateEntitlement(doc, c.query)
+		assert.Equal(t, c.allowed, allowed, c.query)
+		assert.Equal(t, c.value, value, c.query)
+	}
+
+	// 整个文档
+	value, ok := LookupJSONPath(doc, "$")
+	assert.True(t, ok)
+	assert.Equal(t, doc, value)
+}
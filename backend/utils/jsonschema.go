@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// schemaResourceURL 编译时使用的虚拟地址，Schema 不从外部加载
+const schemaResourceURL = "mem://schema.json"
+
+// SchemaViolation 文档中不符合 Schema 的位置及原因
+type SchemaViolation struct {
+	Path    string // JSON Pointer，如 /limits/api_calls，空字符串表示文档本身
+	Message string
+}
+
+// CompileJSONSchema 编译 JSON Schema，未声明 $schema 时按 2020-12 草案处理
+// 禁止通过 $ref 加载外部地址
+func CompileJSONSchema(schema string) (*jsonschema.Schema, error) {
+	compiler := jsonschema.NewCompiler()
+	compiler.Draft = jsonschema.Draft2020
+	compiler.LoadURL = func(s string) (io.ReadCloser, error) {
+		return nil, fmt.Errorf("不允许引用外部Schema: %s", s)
+	}
+	if err := compiler.AddResource(schemaResourceURL, strings.NewReader(schema)); err != nil {
+		return nil, errors.New("JSON Schema格式错误")
+	}
+	compiled, err := compiler.Compile(schemaResourceURL)
+	if err != nil {
+		return nil, fmt.Errorf("JSON Schema无效: %s", schemaErrorMessage(err))
+	}
+	return compiled, nil
+}
+
+// ValidateJSONSchema 校验JSON文档，返回全部不符合的位置，文档符合时返回 nil
+func ValidateJSONSchema(schema *jsonschema.Schema, document string) ([]SchemaViolation, error) {
+	// 数字需按 json.Number 解码，以便 Schema 区分整数和小数
+	var value interface{}
+	decoder := json.NewDecoder(strings.NewReader(document))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, errors.New("JSON格式错误")
+	}
+
+	err := schema.Validate(value)
+	if err == nil {
+		return nil, nil
+	}
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return nil, err
+	}
+
+	var violations []SchemaViolation
+	seen := make(map[SchemaViolation]bool)
+	collectViolations(validationErr, &violations, seen)
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].Path < violations[j].Path
+	})
+	return violations, nil
+}
+
+// JSONPointerToPath 将 JSON Pointer 转换为便于阅读的字段路径，如 /limits/0 转换为 limits[0]
+func JSONPointerToPath(pointer string) string {
+	if pointer == "" {
+		return ""
+	}
+	var b strings.Builder
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		if isArrayIndex(token) {
+			b.WriteString("[" + token + "]")
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString(".")
+		}
+		b.WriteString(token)
+	}
+	return b.String()
+}
+
+// collectViolations 收集最底层的校验错误，上层错误只是对下层错误的汇总
+func collectViolations(err *jsonschema.ValidationError, violations *[]SchemaViolation, seen map[SchemaViolation]bool) {
+	if len(err.Causes) == 0 {
+		v := SchemaViolation{Path: err.InstanceLocation, Message: err.Message}
+		if !seen[v] {
+			seen[v] = true
+			*violations = append(*violations, v)
+		}
+		return
+	}
+	for _, cause := range err.Causes {
+		collectViolations(cause, violations, seen)
+	}
+}
+
+// schemaErrorMessage 提取 Schema 编译错误中最具体的原因
+func schemaErrorMessage(err error) string {
+	var validationErr *jsonschema.ValidationError
+	if errors.As(err, &validationErr) {
+		leaf := validationErr
+		for len(leaf.Causes) > 0 {
+			leaf = leaf.Causes[0]
+		}
+		if leaf.InstanceLocation == "" {
+			return leaf.Message
+		}
+		return JSONPointerToPath(leaf.InstanceLocation) + ": " + leaf.Message
+	}
+	return err.Error()
+}
+
+// isArrayIndex 检查 JSON Pointer 片段是否为数组下标
+func isArrayIndex(token string) bool {
+	if token == "" {
+		return false
+	}
+	for _, c := range token {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
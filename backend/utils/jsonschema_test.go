@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testPermissionSchema = `{
+	"type": "object",
+	"properties": {
+		"features": {"type": "array", "items": {"type": "string"}},
+		"limits": {
+			"type": "object",
+			"properties": {
+				"api_calls": {"type": "integer", "minimum": 0}
+			},
+			"additionalProperties": false
+		}
+	},
+	"required": ["features"]
+}`
+
+// TestValidateJSONSchema 测试按Schema校验文档并定位字段
+func TestValidateJSONSchema(t *testing.T) {
+	schema, err := CompileJSONSchema(testPermissionSchema)
+	assert.NoError(t, err)
+
+	violations, err := ValidateJSONSchema(schema, `{"features": ["basic"], "limits": {"api_calls": 1000}}`)
+	assert.NoError(t, err)
+	assert.Empty(t, violations)
+
+	violations, err = ValidateJSONSchema(schema, `{"features": ["basic", 1], "limits": {"api_calls": -1, "storage": "1GB"}}`)
+	assert.NoError(t, err)
+	paths := make([]string, len(violations))
+	for i, v := range violations {
+		paths[i] = v.Path
+		assert.NotEmpty(t, v.Message)
+	}
+	assert.Equal(t, []string{"/features/1", "/limits", "/limits/api_calls"}, paths)
+
+	violations, err = ValidateJSONSchema(schema, `{}`)
+	assert.NoError(t, err)
+	assert.Len(t, violations, 1)
+	assert.Equal(t, "", violations[0].Path)
+
+	_, err = ValidateJSONSchema(schema, `{invalid`)
+	assert.Error(t, err)
+}
+
+// TestCompileJSONSchema 测试Schema编译错误
+func TestCompileJSONSchema(t *testing.T) {
+	_, err := CompileJSONSchema(`{invalid`)
+	assert.Error(t, err)
+
+	_, err = CompileJSONSchema(`{"type": "unknown"}`)
+	assert.Error(t, err)
+
+	// 不允许加载外部Schema
+	_, err = CompileJSONSchema(`{"$ref": "https://example.com/schema.json"}`)
+	assert.Error(t, err)
+}
+
+// TestJSONPointerToPath 测试JSON Pointer转换为字段路径
+func TestJSONPointerToPath(t *testing.T) {
+	assert.Equal(t, "", JSONPointerToPath(""))
+	assert.Equal(t, "limits.api_calls", JSONPointerToPath("/limits/api_calls"))
+	assert.Equal(t, "features[1]", JSONPointerToPath("/features/1"))
+	assert.Equal(t, "a/b.c~d", JSONPointerToPath("/a~1b/c~0d"))
+}
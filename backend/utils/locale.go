@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// NormalizeLocale 校验BCP-47语言标签并转换为规范形式，如 zh_cn 转换为 zh-CN
+func NormalizeLocale(locale string) (string, error) {
+	tag, err := language.Parse(strings.TrimSpace(locale))
+	if err != nil || tag.IsRoot() {
+		return "", errors.New("无效的语言标签: " + locale)
+	}
+	return tag.String(), nil
+}
+
+// PreferredLocales 解析客户端偏好的语言，lang 参数优先于 Accept-Language 请求头
+// 返回的列表按偏好顺序排列，无法解析的内容会被忽略
+func PreferredLocales(lang, acceptLanguage string) []language.Tag {
+	if lang != "" {
+		if tag, err := language.Parse(lang); err == nil && !tag.IsRoot() {
+			return []language.Tag{tag}
+		}
+	}
+
+	// ParseAcceptLanguage 已按权重从高到低排序，通配符 * 会被解析为 mul
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil {
+		return nil
+	}
+	preferred := make([]language.Tag, 0, len(tags))
+	for _, tag := range tags {
+		if !tag.IsRoot() && tag != language.Make("mul") {
+			preferred = append(preferred, tag)
+		}
+	}
+	return preferred
+}
+
+// LocaleFallbackChain 按偏好顺序展开语言回退链，如 zh-CN 依次回退到 zh-Hans、zh，en-US 回退到 en
+func LocaleFallbackChain(preferred []language.Tag) []string {
+	var chain []string
+	seen := make(map[string]bool)
+	add := func(t language.Tag) {
+		if s := t.String(); !seen[s] {
+			seen[s] = true
+			chain = append(chain, s)
+		}
+	}
+	for _, tag := range preferred {
+		for t := tag; !t.IsRoot(); t = t.Parent() {
+			add(t)
+			if script, ok := scriptFallback(t); ok {
+				add(script)
+			}
+		}
+	}
+	return chain
+}
+
+// scriptFallback 返回带地区的标签对应的“语言-文字”标签，如 zh-CN 对应 zh-Hans，sr-RS 对应 sr-Cyrl
+// 文字在该语言中没有歧义时（如 en-Latn）不需要回退，返回 false
+func scriptFallback(t language.Tag) (language.Tag, bool) {
+	base, _ := t.Base()
+	script, confidence := t.Script()
+	if confidence == language.No || t.String() == base.String() {
+		return language.Tag{}, false
+	}
+	tag, err := language.Compose(base, script)
+	if err != nil || tag == t {
+		return language.Tag{}, false
+	}
+	// SuppressScript 会去掉语言默认且唯一的文字
+	if _, c := language.SuppressScript.Make(tag.String()).Script(); c != language.Exact {
+		return language.Tag{}, false
+	}
+	return tag, true
+}
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNormalizeLocale 测试语言标签规范化
+func TestNormalizeLocale(t *testing.T) {
+	locale, err := NormalizeLocale("zh_cn")
+	assert.NoError(t, err)
+	assert.Equal(t, "zh-CN", locale)
+
+	locale, err = NormalizeLocale("EN")
+	assert.NoError(t, err)
+	assert.Equal(t, "en", locale)
+
+	_, err = NormalizeLocale("不是语言")
+	assert.Error(t, err)
+	_, err = NormalizeLocale("")
+	assert.Error(t, err)
+}
+
+// TestLocaleFallbackChain 测试语言偏好解析及回退链
+func TestLocaleFallbackChain(t *testing.T) {
+	chain := LocaleFallbackChain(PreferredLocales("", "zh-TW,zh;q=0.9,en-US;q=0.8,*;q=0.1"))
+	assert.Equal(t, []string{"zh-TW", "zh-Hant", "zh", "en-US", "en"}, chain)
+
+	// lang 参数优先于请求头
+	chain = LocaleFallbackChain(PreferredLocales("en-GB", "zh-CN"))
+	assert.Equal(t, []string{"en-GB", "en-001", "en"}, chain)
+
+	// 无效的 lang 参数回退到请求头
+	chain = LocaleFallbackChain(PreferredLocales("??", "zh-CN"))
+	assert.Equal(t, []string{"zh-CN", "zh-Hans", "zh"}, chain)
+
+	// 回退链包含地区对应的文字，语言只有一种文字时不展开
+	chain = LocaleFallbackChain(PreferredLocales("", "sr-RS,zh,pt-BR"))
+	assert.Equal(t, []string{"sr-RS", "sr-Cyrl", "sr", "zh", "pt-BR", "pt"}, chain)
+
+	assert.Empty(t, LocaleFallbackChain(PreferredLocales("", "")))
+}
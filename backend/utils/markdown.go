@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// 更新日志输出格式
+const (
+	ChangelogFormatMarkdown = "markdown"
+	ChangelogFormatHTML     = "html"
+	ChangelogFormatText     = "text"
+)
+
+var (
+	// markdownRenderer 支持 CommonMark 及 GFM 扩展（表格、删除线、自动链接、任务列表）
+	// 未开启 unsafe 模式，Markdown 中的原始 HTML 不会被输出
+	markdownRenderer = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+	// htmlPolicy 白名单方式清理渲染结果，防止XSS
+	htmlPolicy = newHTMLPolicy()
+
+	// textPolicy 去除所有标签，用于生成纯文本
+	textPolicy = bluemonday.StrictPolicy()
+
+	blankLinesRegex = regexp.MustCompile(`\n{3,}`)
+)
+
+// newHTMLPolicy 在UGC策略基础上放行GFM任务列表的只读复选框
+func newHTMLPolicy() *bluemonday.Policy {
+	policy := bluemonday.UGCPolicy()
+	policy.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	policy.AllowAttrs("checked", "disabled").OnElements("input")
+	return policy
+}
+
+// RenderMarkdown 将Markdown渲染为经过清理的HTML
+func RenderMarkdown(md string) string {
+	if strings.TrimSpace(md) == "" {
+		return ""
+	}
+
+	var buf bytes.Buffer
+	if err := markdownRenderer.Convert([]byte(md), &buf); err != nil {
+		// 渲染失败时退化为转义后的纯文本
+		return "<p>" + html.EscapeString(md) + "</p>"
+	}
+	return htmlPolicy.Sanitize(buf.String())
+}
+
+// MarkdownToText 将Markdown转换为纯文本
+func MarkdownToText(md string) string {
+	return HTMLToText(RenderMarkdown(md))
+}
+
+// HTMLToText 去除HTML标签并还原实体，返回纯文本
+func HTMLToText(h string) string {
+	text := html.UnescapeString(textPolicy.Sanitize(h))
+	text = blankLinesRegex.ReplaceAllString(text, "\n\n")
+	return strings.TrimSpace(text)
+}
+
+// IsValidChangelogFormat 检查更新日志格式是否受支持
+func IsValidChangelogFormat(format string) bool {
+	switch format {
+	case ChangelogFormatMarkdown, ChangelogFormatHTML, ChangelogFormatText:
+		return true
+	}
+	return false
+}
+
+// FormatChangelog 按指定格式输出更新日志，未知格式按HTML输出
+func FormatChangelog(md, renderedHTML, format string) string {
+	switch format {
+	case ChangelogFormatMarkdown:
+		return md
+	case ChangelogFormatText:
+		return HTMLToText(renderedHTML)
+	}
+	return renderedHTML
+}
@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRenderMarkdown 测试Markdown渲染
+func TestRenderMarkdown(t *testing.T) {
+	html := RenderMarkdown("## 新功能\n\n- 支持**暗黑模式**\n- ~~旧功能~~\n\n| 平台 | 状态 |\n| --- | --- |\n| iOS | ✅ |")
+	assert.Contains(t, html, "<h2")
+	assert.Contains(t, html, "<li>支持<strong>暗黑模式</strong></li>")
+	assert.Contains(t, html, "<del>旧功能</del>")
+	assert.Contains(t, html, "<table>")
+
+	assert.Equal(t, "", RenderMarkdown("   "))
+}
+
+// TestRenderMarkdownSanitize 测试渲染结果的XSS防护
+func TestRenderMarkdownSanitize(t *testing.T) {
+	html := RenderMarkdown("<script>alert(1)</script>\n\n[点击](javascript:alert(1))\n\n<img src=x onerror=alert(1)>")
+	assert.NotContains(t, html, "<script")
+	assert.NotContains(t, html, "javascript:")
+	assert.NotContains(t, html, "onerror")
+
+	html = RenderMarkdown("[官网](https://example.com)")
+	assert.Contains(t, html, `href="https://example.com"`)
+}
+
+// TestRenderMarkdownTaskList 测试任务列表的复选框在清理后保留
+func TestRenderMarkdownTaskList(t *testing.T) {
+	html := RenderMarkdown("- [x] 已完成\n- [ ] 待完成")
+	assert.Contains(t, html, `<input checked="" disabled="" type="checkbox"`)
+	assert.Contains(t, html, `<input disabled="" type="checkbox"`)
+
+	// 其他类型的输入框仍被移除
+	html = RenderMarkdown("<input type=\"text\" value=\"x\">")
+	assert.NotContains(t, html, "<input")
+}
+
+// TestMarkdownToText 测试纯文本输出
+func TestMarkdownToText(t *testing.T) {
+	text := MarkdownToText("## 修复\n\n- 修复 **崩溃** & 卡顿")
+	assert.Contains(t, text, "修复")
+	assert.Contains(t, text, "修复 崩溃 & 卡顿")
+	assert.NotContains(t, text, "<")
+	assert.NotContains(t, text, "**")
+}
+
+// TestFormatChangelog 测试按格式输出更新日志
+func TestFormatChangelog(t *testing.T) {
+	md := "**粗体**"
+	html := RenderMarkdown(md)
+	assert.Equal(t, md, FormatChangelog(md, html, ChangelogFormatMarkdown))
+	assert.Equal(t, html, FormatChangelog(md, html, ChangelogFormatHTML))
+	assert.Equal(t, "粗体", FormatChangelog(md, html, ChangelogFormatText))
+	assert.True(t, IsValidChangelogFormat("text"))
+	assert.False(t, IsValidChangelogFormat("pdf"))
+}
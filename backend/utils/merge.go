@@ -0,0 +1,73 @@
+package utils
+
+import "reflect"
+
+// MergePermissions 深度合并会员权限配置，override 为继承方（较高等级）的配置
+//   - 对象按键递归合并
+//   - 数组取并集，保留 base 中的顺序，重复元素只保留一个
+//   - restrictions 数组整体以 override 为准，较高等级可以用空数组解除全部限制；
+//     未设置 restrictions 时，override 的 features 中列出的功能从继承的限制中移除
+//   - 其他情况（布尔值、数字、字符串、null 及类型不一致）以 override 为准
+//
+// 参数不会被修改
+func MergePermissions(base, override interface{}) interface{} {
+	switch o := override.(type) {
+	case map[string]interface{}:
+		b, ok := base.(map[string]interface{})
+		if !ok {
+			return override
+		}
+		merged := make(map[string]interface{}, len(b)+len(o))
+		for k, v := range b {
+			merged[k] = v
+		}
+		for k, v := range o {
+			bv, exists := merged[k]
+			if exists && k != "restrictions" {
+				merged[k] = MergePermissions(bv, v)
+			} else {
+				merged[k] = v
+			}
+		}
+
+		// 较高等级开放的功能解除继承的同名限制
+		_, hasRestrictions := o["restrictions"]
+		if inherited, ok := b["restrictions"].([]interface{}); ok && !hasRestrictions {
+			if features, ok := o["features"].([]interface{}); ok {
+				restrictions := make([]interface{}, 0, len(inherited))
+				for _, item := range inherited {
+					if !containsValue(features, item) {
+						restrictions = append(restrictions, item)
+					}
+				}
+				merged["restrictions"] = restrictions
+			}
+		}
+		return merged
+	case []interface{}:
+		b, ok := base.([]interface{})
+		if !ok {
+			return override
+		}
+		merged := make([]interface{}, 0, len(b)+len(o))
+		for _, items := range [][]interface{}{b, o} {
+			for _, item := range items {
+				if !containsValue(merged, item) {
+					merged = append(merged, item)
+				}
+			}
+		}
+		return merged
+	}
+	return override
+}
+
+// containsValue 检查数组中是否存在相同的元素
+func containsValue(items []interface{}, value interface{}) bool {
+	for _, item := range items {
+		if reflect.DeepEqual(item, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMergePermissions 测试权限配置深度合并规则
+func TestMergePermissions(t *testing.T) {
+	var base, override interface{}
+	assert.NoError(t, json.Unmarshal([]byte(`{
+		"features": ["basic", "export"],
+		"limits": {"api_calls": 1000, "storage": "1GB"},
+		"flags": {"beta": true, "ads": false},
+		"support": "email"
+	}`), &base))
+	assert.NoError(t, json.Unmarshal([]byte(`{
+		"features": ["advanced", "basic"],
+		"limits": {"api_calls": 5000, "projects": 10},
+		"flags": {"beta": false, "ads": true},
+		"support": {"level": "priority"}
+	}`), &override))
+
+	merged, err := json.Marshal(MergePermissions(base, override))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"features": ["basic", "export", "advanced"],
+		"limits": {"api_calls": 5000, "storage": "1GB", "projects": 10},
+		"flags": {"beta": false, "ads": true},
+		"support": {"level": "priority"}
+	}`, string(merged))
+
+	// 原配置不被修改
+	assert.Len(t, base.(map[string]interface{})["features"], 2)
+
+	// 数组中的对象按内容去重
+	var a, b interface{}
+	json.Unmarshal([]byte(`[{"id": 1}, {"id": 2}]`), &a)
+	json.Unmarshal([]byte(`[{"id": 2}, {"id": 3}]`), &b)
+	merged, _ = json.Marshal(MergePermissions(a, b))
+	assert.JSONEq(t, `[{"id": 1}, {"id": 2}, {"id": 3}]`, string(merged))
+
+	assert.Nil(t, MergePermissions(true, nil))
+}
+
+// TestMergePermissionsRestrictions 测试较高等级解除继承的限制
+func TestMergePermissionsRestrictions(t *testing.T) {
+	var basic, premium, enterprise interface{}
+	json.Unmarshal([]byte(`{"features": ["basic"], "restrictions": ["export", "api"], "ads": true}`), &basic)
+	json.Unmarshal([]byte(`{"features": ["export"], "ads": false}`), &premium)
+	json.Unmarshal([]byte(`{"restrictions": []}`), &enterprise)
+
+	// 高级会员开放 export，解除基础等级的同名限制，布尔值以较高等级为准
+	effective := MergePermissions(basic, premium)
+	merged, _ := json.Marshal(effective)
+	assert.JSONEq(t, `{"features": ["basic", "export"], "restrictions": ["api"], "ads": false}`, string(merged))
+	allowed, _ := EvaluateEntitlement(effective, "export")
+	assert.True(t, allowed)
+	allowed, _ = EvaluateEntitlement(effective, "api")
+	assert.False(t, allowed)
+	allowed, _ = EvaluateEntitlement(MergePermissions(basic, map[string]interface{}{}), "export")
+	assert.False(t, allowed)
+
+	// 显式设置 restrictions 时整体替换继承的限制
+	merged, _ = json.Marshal(MergePermissions(effective, enterprise))
+	assert.JSONEq(t, `{"features": ["basic", "export"], "restrictions": [], "ads": false}`, string(merged))
+
+	// 基础等级不被修改
+	assert.Len(t, basic.(map[string]interface{})["restrictions"], 2)
+}
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"net/http"
+	"strings"
+)
+
+// RequestBaseURL 根据请求推断服务的外部访问地址，支持反向代理设置的 X-Forwarded-Proto/Host
+func RequestBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = strings.TrimSpace(strings.Split(proto, ",")[0])
+	}
+
+	host := r.Host
+	if forwarded := r.Header.Get("X-Forwarded-Host"); forwarded != "" {
+		host = strings.TrimSpace(strings.Split(forwarded, ",")[0])
+	}
+	return scheme + "://" + host
+}
+
+// AbsoluteURL 将站内路径转换为完整地址，已是完整地址时原样返回
+func AbsoluteURL(r *http.Request, p string) string {
+	if !strings.HasPrefix(p, "/") || strings.HasPrefix(p, "//") {
+		return p
+	}
+	return RequestBaseURL(r) + p
+}
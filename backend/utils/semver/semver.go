@@ -0,0 +1,229 @@
+// Package semver 实现语义化版本 2.0.0 (https://semver.org) 的解析与比较
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Version 语义化版本号
+type Version struct {
+	Major      uint64
+	Minor      uint64
+	Patch      uint64
+	Prerelease []string // 预发布标识，如 beta.1 拆分为 ["beta", "1"]
+	Build      []string // 构建元数据，不参与优先级比较
+}
+
+// Parse 解析语义化版本号，如 1.2.3、2.0.0-beta.1、1.4.0+build.77
+func Parse(s string) (*Version, error) {
+	if s == "" {
+		return nil, errors.New("版本号不能为空")
+	}
+
+	v := &Version{}
+	rest := s
+
+	// 构建元数据
+	if i := strings.IndexByte(rest, '+'); i >= 0 {
+		build, err := parseIdentifiers(rest[i+1:], false)
+		if err != nil {
+			return nil, fmt.Errorf("构建元数据无效: %s", s)
+		}
+		v.Build = build
+		rest = rest[:i]
+	}
+
+	// 预发布标识
+	if i := strings.IndexByte(rest, '-'); i >= 0 {
+		prerelease, err := parseIdentifiers(rest[i+1:], true)
+		if err != nil {
+			return nil, fmt.Errorf("预发布标识无效: %s", s)
+		}
+		v.Prerelease = prerelease
+		rest = rest[:i]
+	}
+
+	parts := strings.Split(rest, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("版本号应为 x.y.z 格式: %s", s)
+	}
+	numbers := make([]uint64, 3)
+	for i, part := range parts {
+		if !isNumeric(part) || (len(part) > 1 && part[0] == '0') {
+			return nil, fmt.Errorf("版本号应为 x.y.z 格式: %s", s)
+		}
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("版本号数值超出范围: %s", s)
+		}
+		numbers[i] = n
+	}
+	v.Major, v.Minor, v.Patch = numbers[0], numbers[1], numbers[2]
+
+	return v, nil
+}
+
+// MustParse 解析版本号，失败时panic，仅用于常量版本号
+func MustParse(s string) *Version {
+	v, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// IsValid 检查字符串是否为合法的语义化版本号
+func IsValid(s string) bool {
+	_, err := Parse(s)
+	return err == nil
+}
+
+// parseIdentifiers 解析以点分隔的标识符
+func parseIdentifiers(s string, prerelease bool) ([]string, error) {
+	if s == "" {
+		return nil, errors.New("标识符不能为空")
+	}
+	ids := strings.Split(s, ".")
+	for _, id := range ids {
+		if id == "" {
+			return nil, errors.New("标识符不能为空")
+		}
+		for _, r := range id {
+			if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-') {
+				return nil, errors.New("标识符包含非法字符")
+			}
+		}
+		// 预发布中的纯数字标识符不允许前导零
+		if prerelease && isNumeric(id) && len(id) > 1 && id[0] == '0' {
+			return nil, errors.New("数字标识符不能有前导零")
+		}
+	}
+	return ids, nil
+}
+
+// isNumeric 检查字符串是否只包含数字
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// String 返回版本号字符串
+func (v *Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if len(v.Prerelease) > 0 {
+		s += "-" + strings.Join(v.Prerelease, ".")
+	}
+	if len(v.Build) > 0 {
+		s += "+" + strings.Join(v.Build, ".")
+	}
+	return s
+}
+
+// IsPrerelease 是否为预发布版本
+func (v *Version) IsPrerelease() bool {
+	return len(v.Prerelease) > 0
+}
+
+// Compare 按语义化版本优先级比较，v<o 返回-1，相等返回0，v>o 返回1
+// 构建元数据不参与比较
+func (v *Version) Compare(o *Version) int {
+	if c := compareUint(v.Major, o.Major); c != 0 {
+		return c
+	}
+	if c := compareUint(v.Minor, o.Minor); c != 0 {
+		return c
+	}
+	if c := compareUint(v.Patch, o.Patch); c != 0 {
+		return c
+	}
+
+	// 有预发布标识的版本优先级低于正式版本
+	switch {
+	case len(v.Prerelease) == 0 && len(o.Prerelease) == 0:
+		return 0
+	case len(v.Prerelease) == 0:
+		return 1
+	case len(o.Prerelease) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(v.Prerelease) && i < len(o.Prerelease); i++ {
+		if c := compareIdentifier(v.Prerelease[i], o.Prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	return compareUint(uint64(len(v.Prerelease)), uint64(len(o.Prerelease)))
+}
+
+// LessThan v 的优先级是否低于 o
+func (v *Version) LessThan(o *Version) bool {
+	return v.Compare(o) < 0
+}
+
+// GreaterThan v 的优先级是否高于 o
+func (v *Version) GreaterThan(o *Version) bool {
+	return v.Compare(o) > 0
+}
+
+// Equal v 与 o 的优先级是否相同
+func (v *Version) Equal(o *Version) bool {
+	return v.Compare(o) == 0
+}
+
+// compareIdentifier 比较单个预发布标识符，数字标识符按数值比较且低于非数字标识符
+func compareIdentifier(a, b string) int {
+	aNum, bNum := isNumeric(a), isNumeric(b)
+	switch {
+	case aNum && bNum:
+		if len(a) != len(b) {
+			return compareUint(uint64(len(a)), uint64(len(b)))
+		}
+		return strings.Compare(a, b)
+	case aNum:
+		return -1
+	case bNum:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// Compare 比较两个版本号字符串，任一无法解析时返回错误
+func Compare(a, b string) (int, error) {
+	va, err := Parse(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := Parse(b)
+	if err != nil {
+		return 0, err
+	}
+	return va.Compare(vb), nil
+}
+
+// Sort 按优先级从低到高排序
+func Sort(versions []*Version) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].LessThan(versions[j])
+	})
+}
@@ -0,0 +1,78 @@
+package semver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParse 测试版本号解析
+func TestParse(t *testing.T) {
+	v, err := Parse("2.0.0-beta.1")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), v.Major)
+	assert.Equal(t, []string{"beta", "1"}, v.Prerelease)
+	assert.True(t, v.IsPrerelease())
+
+	v, err = Parse("1.4.0+build.77")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"build", "77"}, v.Build)
+	assert.False(t, v.IsPrerelease())
+	assert.Equal(t, "1.4.0+build.77", v.String())
+
+	v, err = Parse("1.0.0-rc.1+exp.sha.5114f85")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0.0-rc.1+exp.sha.5114f85", v.String())
+
+	invalid := []string{"", "1", "1.2", "1.2.3.4", "01.2.3", "1.02.3", "v1.2.3", "1.2.3-", "1.2.3-beta..1", "1.2.3-01", "1.2.3+", "1.2.3-beta_1", "a.b.c"}
+	for _, s := range invalid {
+		_, err := Parse(s)
+		assert.Error(t, err, s)
+	}
+}
+
+// TestCompare 测试版本优先级，用例来自 semver.org 规范
+func TestCompare(t *testing.T) {
+	ordered := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.1.0",
+		"2.0.0",
+		"10.0.0",
+	}
+	for i := 0; i < len(ordered)-1; i++ {
+		c, err := Compare(ordered[i], ordered[i+1])
+		assert.NoError(t, err)
+		assert.Equal(t, -1, c, "%s < %s", ordered[i], ordered[i+1])
+
+		c, _ = Compare(ordered[i+1], ordered[i])
+		assert.Equal(t, 1, c, "%s > %s", ordered[i+1], ordered[i])
+	}
+
+	// 构建元数据不影响优先级
+	c, err := Compare("1.4.0+build.77", "1.4.0+build.78")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, c)
+
+	_, err = Compare("1.0.0", "invalid")
+	assert.Error(t, err)
+}
+
+// TestSort 测试排序
+func TestSort(t *testing.T) {
+	versions := []*Version{MustParse("1.10.0"), MustParse("1.2.0"), MustParse("1.2.0-beta.1"), MustParse("0.9.9")}
+	Sort(versions)
+
+	var result []string
+	for _, v := range versions {
+		result = append(result, v.String())
+	}
+	assert.Equal(t, []string{"0.9.9", "1.2.0-beta.1", "1.2.0", "1.10.0"}, result)
+}
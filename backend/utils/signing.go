@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// sealedSecretPrefix 加密存储的密文前缀，用于区分加密前写入的明文
+const sealedSecretPrefix = "enc:"
+
+// GenerateSigningKey 生成 Ed25519 密钥对，返回 Base64 编码的公钥和私钥种子
+func GenerateSigningKey() (string, string, error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	return base64.StdEncoding.EncodeToString(publicKey),
+		base64.StdEncoding.EncodeToString(privateKey.Seed()), nil
+}
+
+// SigningKeyID 根据公钥计算密钥ID
+func SigningKeyID(publicKey string) string {
+	sum := sha256.Sum256([]byte(publicKey))
+	return hex.EncodeToString(sum[:8])
+}
+
+// CanonicalJSON 生成规范化JSON：对象键按字典序排列，无多余空白，不转义HTML字符
+func CanonicalJSON(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	// 重新解析为通用结构，使结构体字段也按键排序；数字保持原始写法
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(generic); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// SignEd25519 使用 Base64 编码的私钥种子签名，返回 Base64 编码的签名
+func SignEd25519(privateKey string, data []byte) (string, error) {
+	seed, err := base64.StdEncoding.DecodeString(privateKey)
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return "", errors.New("无效的签名私钥")
+	}
+	signature := ed25519.Sign(ed25519.NewKeyFromSeed(seed), data)
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+// VerifyEd25519 使用 Base64 编码的公钥校验签名
+func VerifyEd25519(publicKey string, data []byte, signature string) bool {
+	key, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return false
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(key, data, sig)
+}
+
+// SealSecret 使用 AES-256-GCM 加密敏感数据，密钥由 secret 派生，返回带前缀的 Base64 密文
+func SealSecret(secret, plaintext string) (string, error) {
+	aead, err := secretAEAD(secret)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return sealedSecretPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// OpenSecret 解密 SealSecret 生成的密文，不带前缀的值视为加密前写入的明文原样返回
+func OpenSecret(secret, value string) (string, error) {
+	if !IsSealedSecret(value) {
+		return value, nil
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, sealedSecretPrefix))
+	if err != nil {
+		return "", errors.New("无效的密文")
+	}
+	aead, err := secretAEAD(secret)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < aead.NonceSize() {
+		return "", errors.New("无效的密文")
+	}
+	plaintext, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+	if err != nil {
+		return "", errors.New("解密失败，请检查加密密钥配置")
+	}
+	return string(plaintext), nil
+}
+
+// IsSealedSecret 判断值是否为 SealSecret 生成的密文
+func IsSealedSecret(value string) bool {
+	return strings.HasPrefix(value, sealedSecretPrefix)
+}
+
+// secretAEAD 根据 secret 派生 AES-256-GCM 加密器
+func secretAEAD(secret string) (cipher.AEAD, error) {
+	key := sha256.Sum256([]byte(secret))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCanonicalJSON 测试规范化JSON
+func TestCanonicalJSON(t *testing.T) {
+	payload := struct {
+		Version   string                 `json:"version"`
+		Changelog string                 `json:"changelog"`
+		Extra     map[string]interface{} `json:"extra"`
+	}{
+		Version:   "1.2.0",
+		Changelog: "<p>修复 & 改进</p>",
+		Extra:     map[string]interface{}{"z": 1, "a": []int{3, 2}},
+	}
+
+	data, err := CanonicalJSON(payload)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"changelog":"<p>修复 & 改进</p>","extra":{"a":[3,2],"z":1},"version":"1.2.0"}`, string(data))
+}
+
+// TestSignEd25519 测试 Ed25519 签名与校验
+func TestSignEd25519(t *testing.T) {
+	publicKey, privateKey, err := GenerateSigningKey()
+	assert.NoError(t, err)
+	assert.Len(t, SigningKeyID(publicKey), 16)
+
+	data := []byte(`{"version":"1.2.0"}`)
+	signature, err := SignEd25519(privateKey, data)
+	assert.NoError(t, err)
+	assert.True(t, VerifyEd25519(publicKey, data, signature))
+	assert.False(t, VerifyEd25519(publicKey, []byte(`{"version":"9.9.9"}`), signature))
+
+	otherPublicKey, _, _ := GenerateSigningKey()
+	assert.False(t, VerifyEd25519(otherPublicKey, data, signature))
+
+	_, err = SignEd25519("invalid", data)
+	assert.Error(t, err)
+}
+
+// TestSealSecret 测试敏感数据的加密存储
+func TestSealSecret(t *testing.T) {
+	_, privateKey, err := GenerateSigningKey()
+	assert.NoError(t, err)
+
+	sealed, err := SealSecret("secret", privateKey)
+	assert.NoError(t, err)
+	assert.NotContains(t, sealed, privateKey)
+	assert.True(t, IsSealedSecret(sealed))
+	assert.False(t, IsSealedSecret(privateKey))
+
+	opened, err := OpenSecret("secret", sealed)
+	assert.NoError(t, err)
+	assert.Equal(t, privateKey, opened)
+
+	// 密钥错误时解密失败
+	_, err = OpenSecret("other", sealed)
+	assert.Error(t, err)
+
+	// 加密前写入的明文原样返回
+	opened, err = OpenSecret("secret", privateKey)
+	assert.NoError(t, err)
+	assert.Equal(t, privateKey, opened)
+}
@@ -2,7 +2,8 @@
 
 import { useEffect, useState } from "react";
 import { useRouter } from "next/navigation";
-import { systemApi, authApi } from "@/lib/api";
+import { systemApi, authApi, appApi } from "@/lib/api";
+import type { Application } from "@/types/app";
 import { Loader2, Plus, Save, RefreshCw } from "lucide-react";
 import { useMemberLevels } from "@/hooks/useMemberLevels";
 import { Button } from "@/components/ui/button";
@@ -59,8 +60,17 @@ export default function MemberPage() {
   const [editingLevel, setEditingLevel] = useState<number | null>(null);
   const [memberLevels, setMemberLevels] = useState(defaultMemberLevels);
   const [saving, setSaving] = useState(false);
+  const [apps, setApps] = useState<Application[]>([]);
+  const [selectedAppId, setSelectedAppId] = useState<number | null>(null);
 
-  const { loading, error, updateMemberLevels } = useMemberLevels();
+  const { memberLevels: savedLevels, loading, error, updateMemberLevels } = useMemberLevels(selectedAppId);
+
+  // 切换应用后使用该应用已保存的会员等级，未配置时使用默认配置
+  useEffect(() => {
+    if (Array.isArray(savedLevels)) {
+      setMemberLevels(savedLevels.length > 0 ? savedLevels : defaultMemberLevels);
+    }
+  }, [savedLevels]);
 
   useEffect(() => {
     const checkSystemStatus = async () => {
@@ -77,6 +87,11 @@ export default function MemberPage() {
           router.replace("/login");
           return;
         }
+        const appList = await appApi.getApplications();
+        setApps(appList);
+        if (appList.length > 0) {
+          setSelectedAppId(appList[0].id);
+        }
         setSystemLoading(false);
       } catch (error) {
         toast.error("检查系统状态失败");
@@ -155,6 +170,15 @@ export default function MemberPage() {
           <p className="text-gray-600 mt-1">配置会员等级和权限策略</p>
         </div>
         <div className="flex gap-2">
+          <select
+            value={selectedAppId ?? ""}
+            onChange={(e) => setSelectedAppId(Number(e.target.value))}
+            className="border rounded-md px-3 text-sm"
+          >
+            {apps.map((app) => (
+              <option key={app.id} value={app.id}>{app.name}</option>
+            ))}
+          </select>
           <Button variant="outline" onClick={handleResetToDefault}>
             <RefreshCw className="h-4 w-4 mr-2" />
             重置默认
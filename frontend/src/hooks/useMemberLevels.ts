@@ -1,17 +1,21 @@
 import { useState, useEffect } from 'react';
 import { memberApi } from '@/lib/api';
 
-export function useMemberLevels() {
+export function useMemberLevels(appId: number | null) {
   const [memberLevels, setMemberLevels] = useState<any>(null);
   const [loading, setLoading] = useState(true);
   const [error, setError] = useState<string | null>(null);
 
   // 获取会员等级配置
   const fetchMemberLevels = async () => {
+    if (appId === null) {
+      setLoading(false);
+      return;
+    }
     try {
       setLoading(true);
       setError(null);
-      const data = await memberApi.getMemberLevels();
+      const data = await memberApi.getMemberLevels(appId);
       setMemberLevels(data);
     } catch (err) {
       setError(err instanceof Error ? err.message : '获取会员等级配置失败');
@@ -22,8 +26,11 @@ export function useMemberLevels() {
 
   // 更新会员等级配置
   const updateMemberLevels = async (data: any) => {
+    if (appId === null) {
+      throw new Error('请先选择应用');
+    }
     try {
-      const updatedData = await memberApi.updateMemberLevels(data);
+      const updatedData = await memberApi.updateMemberLevels(appId, data);
       setMemberLevels(updatedData);
       return updatedData;
     } catch (err) {
@@ -34,7 +41,7 @@ export function useMemberLevels() {
   // 初始化加载
   useEffect(() => {
     fetchMemberLevels();
-  }, []);
+  }, [appId]);
 
   return {
     memberLevels,
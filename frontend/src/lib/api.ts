@@ -228,16 +228,16 @@ export const authApi = {
   },
 };
 
-// 会员管理API
+// 会员管理API，会员等级按应用管理
 export const memberApi = {
-  getMemberLevels: (): Promise<any> =>
-    request<{code: number; data: {levels: any[]}; message: string}>('/member/levels').then(res => res.data.levels),
+  getMemberLevels: (appId: number): Promise<any> =>
+    request<{code: number; data: {levels: any[]}; message: string}>(`/apps/${appId}/member-levels`).then(res => res.data.levels),
 
-  updateMemberLevels: (levels: any[]): Promise<any[]> =>
-    request<{code: number; data: any[]; message: string}>('/member/levels', {
+  updateMemberLevels: (appId: number, levels: any[]): Promise<any[]> =>
+    request<{code: number; data: {levels: any[]}; message: string}>(`/apps/${appId}/member-levels`, {
       method: 'PUT',
       body: JSON.stringify({ levels }),
-    }).then(res => res.data),
+    }).then(res => res.data.levels),
 };
 
  
\ No newline at end of file
@@ -4,7 +4,9 @@ export interface Application {
   description: string;
   latestVersion: string;
   status: 'active' | 'maintenance' | 'deprecated';
-  apiKey: string;
+  apiKey?: string;
+  apiKeyPrefix: string;
+  publicFeedEnabled?: boolean;
   createdAt: string;
   updatedAt: string;
   versions?: Version[];
@@ -35,5 +37,7 @@ export interface MemberLevel {
   name: string;
   level: number;
   permissions: string;
+  parentLevel?: number | null; // 为空时继承相邻的较低等级
+  standalone?: boolean; // 不继承任何等级
   createdAt: string;
 } 
\ No newline at end of file